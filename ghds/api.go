@@ -0,0 +1,91 @@
+package ghds
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// HistorySummary totals the releases of a repository.
+type HistorySummary struct {
+	Repository     string `json:"repository"`
+	ReleaseCount   int    `json:"release_count"`
+	AssetCount     int    `json:"asset_count"`
+	TotalDownloads int    `json:"total_downloads"`
+	LatestRelease  string `json:"latest_release,omitempty"`
+}
+
+// Summary returns the totals of a history. The latest release is the most
+// recent one that is neither a draft nor a pre-release, if any.
+func (history *ReleaseHistory) Summary() *HistorySummary {
+	summary := &HistorySummary{
+		Repository:     history.Repository,
+		ReleaseCount:   len(history.Releases),
+		TotalDownloads: history.TotalDownloads(),
+	}
+	for _, rel := range history.Releases {
+		summary.AssetCount += len(rel.Assets)
+	}
+	if latest := latestRelease(history.Releases); latest != nil {
+		summary.LatestRelease = releaseKey(*latest)
+	}
+	return summary
+}
+
+// NewAPIHandler returns a handler serving the repositories in cache as JSON:
+//
+//	/repos/{owner}/{repo}/releases        the ReleaseHistory
+//	/repos/{owner}/{repo}/releases/{tag}  a Release, by tag, name or "latest"
+//	/repos/{owner}/{repo}/assets          the ReleaseAssets of every release
+//	/repos/{owner}/{repo}/summary         a HistorySummary
+func NewAPIHandler(cache *HistoryCache) http.Handler {
+	mux := http.NewServeMux()
+	handle := func(pattern string, fn func(history *ReleaseHistory, r *http.Request) (interface{}, error)) {
+		mux.HandleFunc("GET /repos/{owner}/{repo}"+pattern, func(w http.ResponseWriter, r *http.Request) {
+			history, err := cache.Get(r.Context(), r.PathValue("owner")+"/"+r.PathValue("repo"))
+			if err != nil {
+				if errors.Is(err, ErrUnknownRepository) {
+					writeAPIError(w, http.StatusNotFound, "repository not found")
+				} else {
+					writeAPIError(w, http.StatusBadGateway, "fetching release history failed: "+err.Error())
+				}
+				return
+			}
+
+			v, err := fn(history, r)
+			if err != nil {
+				writeAPIError(w, http.StatusNotFound, err.Error())
+				return
+			}
+			writeAPIResponse(w, http.StatusOK, v)
+		})
+	}
+
+	handle("/releases", func(history *ReleaseHistory, r *http.Request) (interface{}, error) {
+		return history, nil
+	})
+	handle("/releases/{tag...}", func(history *ReleaseHistory, r *http.Request) (interface{}, error) {
+		return findRelease(history, r.PathValue("tag"))
+	})
+	handle("/assets", func(history *ReleaseHistory, r *http.Request) (interface{}, error) {
+		assets := []ReleaseAsset{}
+		for _, rel := range history.Releases {
+			assets = append(assets, rel.Assets...)
+		}
+		return assets, nil
+	})
+	handle("/summary", func(history *ReleaseHistory, r *http.Request) (interface{}, error) {
+		return history.Summary(), nil
+	})
+	return mux
+}
+
+func writeAPIError(w http.ResponseWriter, status int, message string) {
+	writeAPIResponse(w, status, map[string]string{"message": message})
+}
+
+func writeAPIResponse(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,60 @@
+package ghds
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAPIHandler(t *testing.T) {
+	cache := NewHistoryCache(time.Minute, map[string]DownloadStatsService{
+		"foo/bar":    &countingService{history: svgHistory},
+		"foo/broken": &countingService{err: errors.New("boom")},
+	})
+	handler := NewAPIHandler(cache)
+
+	cases := []struct {
+		method   string
+		path     string
+		status   int
+		expected string
+	}{
+		{"GET", "/repos/foo/bar/releases", http.StatusOK, `{"repository":"foo/bar","releases":[{"id":0,"name":"v2.0.0-rc.1"`},
+		{"GET", "/repos/foo/bar/releases/v1.0.0", http.StatusOK, `{"id":0,"name":"First \u003crelease\u003e","tag_name":"v1.0.0"`},
+		{"GET", "/repos/foo/bar/releases/latest", http.StatusOK, `{"id":0,"name":"Second","tag_name":"v1.1.0"`},
+		{"GET", "/repos/foo/bar/releases/v3.0.0", http.StatusNotFound, `{"message":"foo/bar: release \"v3.0.0\" not found"}`},
+		{"GET", "/repos/foo/bar/assets", http.StatusOK, `[]`},
+		{"GET", "/repos/Foo/Bar/summary", http.StatusOK,
+			`{"repository":"foo/bar","release_count":3,"asset_count":0,"total_downloads":18395,"latest_release":"v1.1.0"}`},
+		{"GET", "/repos/foo/baz/summary", http.StatusNotFound, `{"message":"repository not found"}`},
+		{"GET", "/repos/foo/broken/summary", http.StatusBadGateway, `{"message":"fetching release history failed: boom"}`},
+		{"POST", "/repos/foo/bar/summary", http.StatusMethodNotAllowed, ""},
+	}
+	for _, c := range cases {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(c.method, c.path, nil))
+		if rec.Code != c.status {
+			t.Errorf("%s %s: got status %d, expected %d", c.method, c.path, rec.Code, c.status)
+		}
+		if !strings.HasPrefix(rec.Body.String(), c.expected) {
+			t.Errorf("%s %s: got %s, expected it to start with %s", c.method, c.path, rec.Body.String(), c.expected)
+		}
+	}
+}
+
+func TestHistorySummary(t *testing.T) {
+	history := &ReleaseHistory{
+		Repository: "foo/bar",
+		Releases: []Release{
+			{Tag: "v2.0.0-rc.1", Prerelease: true, Assets: []ReleaseAsset{{Downloads: 1}}, TotalDownloads: 1},
+			{Tag: "v1.0.0", Assets: []ReleaseAsset{{Downloads: 2}, {Downloads: 3}}, TotalDownloads: 5},
+		},
+	}
+	expected := HistorySummary{Repository: "foo/bar", ReleaseCount: 2, AssetCount: 3, TotalDownloads: 6, LatestRelease: "v1.0.0"}
+	if actual := *history.Summary(); actual != expected {
+		t.Errorf("got %+v, expected %+v", actual, expected)
+	}
+}
@@ -0,0 +1,103 @@
+package ghds
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// regexpPrefix marks an asset pattern as a regular expression rather than a
+// glob.
+const regexpPrefix = "re:"
+
+// ChecksumAssetPatterns match checksum, signature, certificate and SBOM files,
+// which are mostly downloaded by automated tooling rather than users.
+var ChecksumAssetPatterns = []string{
+	`re:(?i)(^|[._-])(checksums?|sha(1|256|512)?sums?)(\.txt)?$`,
+	`re:(?i)\.(md5|sha1|sha256|sha512)(sum)?$`,
+	`re:(?i)\.(sig|asc|pem|crt|cert|minisig)$`,
+	`re:(?i)\.(sigstore|intoto)(\.jsonl?)?$`,
+	`re:(?i)\.(sbom|spdx|cdx|bom)(\.json|\.xml)?$`,
+}
+
+// AssetFilter selects release assets by name. Patterns are globs as accepted
+// by path.Match, or regular expressions when prefixed with "re:".
+type AssetFilter struct {
+	include []assetPattern
+	exclude []assetPattern
+}
+
+type assetPattern struct {
+	glob string
+	re   *regexp.Regexp
+}
+
+func (p assetPattern) match(name string) bool {
+	if p.re != nil {
+		return p.re.MatchString(name)
+	}
+	matched, _ := path.Match(p.glob, name)
+	return matched
+}
+
+// NewAssetFilter returns a filter that keeps assets matching any of the
+// include patterns, or every asset when there are none, unless they also
+// match one of the exclude patterns.
+func NewAssetFilter(include []string, exclude []string) (*AssetFilter, error) {
+	f := &AssetFilter{}
+	var err error
+	if f.include, err = compileAssetPatterns(include); err != nil {
+		return nil, err
+	}
+	if f.exclude, err = compileAssetPatterns(exclude); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func compileAssetPatterns(patterns []string) ([]assetPattern, error) {
+	compiled := make([]assetPattern, 0, len(patterns))
+	for _, pattern := range patterns {
+		if strings.HasPrefix(pattern, regexpPrefix) {
+			re, err := regexp.Compile(strings.TrimPrefix(pattern, regexpPrefix))
+			if err != nil {
+				return nil, fmt.Errorf("asset pattern %q: %s", pattern, err)
+			}
+			compiled = append(compiled, assetPattern{re: re})
+			continue
+		}
+
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("asset pattern %q: %s", pattern, err)
+		}
+		compiled = append(compiled, assetPattern{glob: pattern})
+	}
+	return compiled, nil
+}
+
+// Match reports whether an asset with the given name should be kept. A nil
+// filter keeps every asset.
+func (f *AssetFilter) Match(name string) bool {
+	if f == nil {
+		return true
+	}
+
+	included := len(f.include) == 0
+	for _, p := range f.include {
+		if p.match(name) {
+			included = true
+			break
+		}
+	}
+	if !included {
+		return false
+	}
+
+	for _, p := range f.exclude {
+		if p.match(name) {
+			return false
+		}
+	}
+	return true
+}
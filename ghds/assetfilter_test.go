@@ -0,0 +1,77 @@
+package ghds
+
+import (
+	"testing"
+)
+
+func TestAssetFilter(t *testing.T) {
+	var filterTests = []struct {
+		include  []string
+		exclude  []string
+		name     string
+		expected bool
+	}{
+		{nil, nil, "tool_1.2.3_linux_amd64.tar.gz", true},
+		{[]string{"*linux*"}, nil, "tool_1.2.3_linux_amd64.tar.gz", true},
+		{[]string{"*linux*"}, nil, "tool_1.2.3_darwin_arm64.zip", false},
+		{[]string{"*.zip", "*.tar.gz"}, nil, "tool_1.2.3_darwin_arm64.zip", true},
+		{nil, []string{"*.txt"}, "checksums.txt", false},
+		{nil, []string{"re:_(386|arm)\\."}, "tool_1.2.3_linux_386.tar.gz", false},
+		{nil, []string{"re:_(386|arm)\\."}, "tool_1.2.3_linux_arm64.tar.gz", true},
+		{[]string{"re:^tool_"}, []string{"*windows*"}, "tool_1.2.3_windows_amd64.zip", false},
+	}
+
+	for _, tt := range filterTests {
+		filter, err := NewAssetFilter(tt.include, tt.exclude)
+		if err != nil {
+			t.Errorf("NewAssetFilter(%v, %v): unexpected error: %s", tt.include, tt.exclude, err)
+			continue
+		}
+		if actual := filter.Match(tt.name); actual != tt.expected {
+			t.Errorf("NewAssetFilter(%v, %v).Match(%s): expected %v, actual %v", tt.include, tt.exclude, tt.name, tt.expected, actual)
+		}
+	}
+
+	var filter *AssetFilter
+	if !filter.Match("anything") {
+		t.Errorf("expected a nil filter to match every asset")
+	}
+}
+
+func TestAssetFilterInvalidPatterns(t *testing.T) {
+	if _, err := NewAssetFilter([]string{"["}, nil); err == nil {
+		t.Errorf("expected an error for an invalid glob")
+	}
+	if _, err := NewAssetFilter(nil, []string{"re:("}); err == nil {
+		t.Errorf("expected an error for an invalid regular expression")
+	}
+}
+
+func TestChecksumAssetPatterns(t *testing.T) {
+	filter, err := NewAssetFilter(nil, ChecksumAssetPatterns)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	excluded := []string{
+		"checksums.txt", "tool_1.2.3_checksums.txt", "SHA256SUMS", "sha256sums.txt",
+		"tool_1.2.3_linux_amd64.tar.gz.sha256", "tool.zip.md5", "tool_1.2.3_linux_amd64.tar.gz.sig",
+		"SHA256SUMS.asc", "tool.pem", "tool.tar.gz.minisig", "tool.sigstore.json", "multiple.intoto.jsonl",
+		"tool_1.2.3.sbom.json", "tool.spdx.json", "tool.cdx.xml",
+	}
+	for _, name := range excluded {
+		if filter.Match(name) {
+			t.Errorf("expected %s to be excluded", name)
+		}
+	}
+
+	kept := []string{
+		"tool_1.2.3_linux_amd64.tar.gz", "tool_1.2.3_windows_amd64.zip", "tool-1.2.3.dmg",
+		"tool_1.2.3_amd64.deb", "signal-desktop.AppImage", "design.pdf",
+	}
+	for _, name := range kept {
+		if !filter.Match(name) {
+			t.Errorf("expected %s to be kept", name)
+		}
+	}
+}
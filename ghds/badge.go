@@ -0,0 +1,95 @@
+package ghds
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// badgeErrorCacheSeconds is how long error badges may be cached, so that
+// e.g. a new release or a GitHub outage is soon reflected.
+const badgeErrorCacheSeconds = 60
+
+// EndpointBadge is the JSON schema of a shields.io endpoint badge, see
+// https://shields.io/badges/endpoint-badge.
+type EndpointBadge struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+	IsError       bool   `json:"isError,omitempty"`
+	CacheSeconds  int    `json:"cacheSeconds,omitempty"`
+}
+
+// Endpoint returns the badge in the shields.io endpoint badge schema.
+func (b *Badge) Endpoint() *EndpointBadge {
+	return &EndpointBadge{
+		SchemaVersion: 1,
+		Label:         b.Label,
+		Message:       b.Message,
+		Color:         strings.TrimPrefix(b.Color, "#"),
+	}
+}
+
+// NewBadgeHandler returns a handler serving shields.io endpoint badges for the
+// repositories in cache on /badge/{owner}/{repo}, counting the downloads of
+// all releases, and on /badge/{owner}/{repo}/{tag}, counting the downloads of
+// one release or, for "latest", of the latest release.
+//
+// Errors are served as badges too, with status 200, as shields.io only shows
+// the badges of successful responses.
+func NewBadgeHandler(cache *HistoryCache) http.Handler {
+	mux := http.NewServeMux()
+	serve := func(w http.ResponseWriter, r *http.Request) {
+		serveBadge(w, r, cache)
+	}
+	mux.HandleFunc("GET /badge/{owner}/{repo}", serve)
+	mux.HandleFunc("GET /badge/{owner}/{repo}/{tag...}", serve)
+	return mux
+}
+
+func serveBadge(w http.ResponseWriter, r *http.Request, cache *HistoryCache) {
+	repository := r.PathValue("owner") + "/" + r.PathValue("repo")
+	tag := r.PathValue("tag")
+
+	history, err := cache.Get(r.Context(), repository)
+	if err != nil {
+		if errors.Is(err, ErrUnknownRepository) {
+			writeBadgeError(w, "repository not found")
+		} else {
+			writeBadgeError(w, "unavailable")
+		}
+		return
+	}
+
+	badge, err := DownloadsBadge(history, tag)
+	if err != nil {
+		writeBadgeError(w, "release not found")
+		return
+	}
+
+	endpoint := badge.Endpoint()
+	endpoint.CacheSeconds = int(cache.TTL().Seconds())
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", endpoint.CacheSeconds))
+	writeBadge(w, http.StatusOK, endpoint)
+}
+
+func writeBadgeError(w http.ResponseWriter, message string) {
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", badgeErrorCacheSeconds))
+	writeBadge(w, http.StatusOK, &EndpointBadge{
+		SchemaVersion: 1,
+		Label:         "downloads",
+		Message:       message,
+		Color:         "lightgrey",
+		IsError:       true,
+		CacheSeconds:  badgeErrorCacheSeconds,
+	})
+}
+
+func writeBadge(w http.ResponseWriter, status int, badge *EndpointBadge) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(badge)
+}
@@ -0,0 +1,48 @@
+package ghds
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBadgeHandler(t *testing.T) {
+	cache := NewHistoryCache(10*time.Minute, map[string]DownloadStatsService{
+		"foo/bar":    &countingService{history: svgHistory},
+		"foo/broken": &countingService{err: errors.New("boom")},
+	})
+	handler := NewBadgeHandler(cache)
+
+	cases := []struct {
+		path     string
+		status   int
+		expected EndpointBadge
+	}{
+		{"/badge/foo/bar", http.StatusOK, EndpointBadge{1, "downloads", "18.4k", "4c1", false, 600}},
+		{"/badge/foo/bar/latest", http.StatusOK, EndpointBadge{1, "downloads@latest", "12.3k", "4c1", false, 600}},
+		{"/badge/foo/bar/v1.0.0", http.StatusOK, EndpointBadge{1, "downloads@v1.0.0", "6k", "4c1", false, 600}},
+		{"/badge/foo/bar/v3.0.0", http.StatusOK, EndpointBadge{1, "downloads", "release not found", "lightgrey", true, 60}},
+		{"/badge/foo/baz", http.StatusOK, EndpointBadge{1, "downloads", "repository not found", "lightgrey", true, 60}},
+		{"/badge/foo/broken", http.StatusOK, EndpointBadge{1, "downloads", "unavailable", "lightgrey", true, 60}},
+	}
+	for _, c := range cases {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", c.path, nil))
+		if rec.Code != c.status {
+			t.Errorf("%s: got status %d, expected %d", c.path, rec.Code, c.status)
+		}
+
+		actual := EndpointBadge{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &actual); err != nil {
+			t.Errorf("%s: unexpected error: %s", c.path, err)
+			continue
+		}
+		if !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("%s: got %+v, expected %+v", c.path, actual, c.expected)
+		}
+	}
+}
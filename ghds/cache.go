@@ -0,0 +1,152 @@
+package ghds
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cacheFetchTimeout bounds a shared fetch, which no request can cancel.
+const cacheFetchTimeout = time.Minute
+
+// ErrUnknownRepository is returned by HistoryCache for repositories it has no
+// service for.
+var ErrUnknownRepository = errors.New("unknown repository")
+
+type cacheEntry struct {
+	history   *ReleaseHistory
+	fetchedAt time.Time
+}
+
+// cacheCall is a fetch in progress, shared by every request for the
+// repository until it completes.
+type cacheCall struct {
+	done    chan struct{}
+	history *ReleaseHistory
+	err     error
+}
+
+// HistoryCache fetches the release history of a set of repositories on demand
+// and keeps it for a time to live, so that frequent requests, e.g. for
+// badges, only reach the GitHub API once per TTL. Concurrent requests for a
+// repository that is not cached share a single fetch.
+type HistoryCache struct {
+	ttl      time.Duration
+	services map[string]DownloadStatsService
+	entries  map[string]*cacheEntry
+	calls    map[string]*cacheCall
+	mu       sync.Mutex
+
+	now          func() time.Time
+	fetchTimeout time.Duration
+}
+
+// NewHistoryCache returns a cache of the repositories in services, which
+// maps full repository names such as "owner/repo" to their service. Names
+// are matched case-insensitively, as on GitHub.
+func NewHistoryCache(ttl time.Duration, services map[string]DownloadStatsService) *HistoryCache {
+	c := &HistoryCache{
+		ttl:      ttl,
+		services: map[string]DownloadStatsService{},
+		entries:  map[string]*cacheEntry{},
+		calls:    map[string]*cacheCall{},
+
+		now:          time.Now,
+		fetchTimeout: cacheFetchTimeout,
+	}
+	for name, dss := range services {
+		c.services[strings.ToLower(name)] = dss
+	}
+	return c
+}
+
+// TTL returns how long a fetched release history is kept.
+func (c *HistoryCache) TTL() time.Duration {
+	return c.ttl
+}
+
+// Get returns the release history of repository, fetching it if it is not
+// cached or has expired. If fetching fails, an expired history is returned
+// rather than the error.
+func (c *HistoryCache) Get(ctx context.Context, repository string) (*ReleaseHistory, error) {
+	key := strings.ToLower(repository)
+	dss, ok := c.services[key]
+	if !ok {
+		return nil, ErrUnknownRepository
+	}
+
+	c.mu.Lock()
+	entry := c.entries[key]
+	if entry != nil && c.now().Sub(entry.fetchedAt) < c.ttl {
+		c.mu.Unlock()
+		return entry.history, nil
+	}
+	call, ok := c.calls[key]
+	if !ok {
+		call = &cacheCall{done: make(chan struct{})}
+		c.calls[key] = call
+		// The fetch is shared, so it must not be cancelled when the request
+		// that started it is, but it is bounded by fetchTimeout.
+		go c.fetch(context.WithoutCancel(ctx), key, dss, call)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-call.done:
+	}
+	if call.err != nil {
+		if entry != nil {
+			return entry.history, nil
+		}
+		return nil, call.err
+	}
+	return call.history, nil
+}
+
+// Put stores a release history fetched elsewhere, such as by an Exporter, as
+// if the cache had just fetched it. Histories of repositories the cache has
+// no service for are ignored.
+func (c *HistoryCache) Put(history *ReleaseHistory) {
+	key := strings.ToLower(history.Repository)
+	if _, ok := c.services[key]; !ok {
+		return
+	}
+
+	c.mu.Lock()
+	c.entries[key] = &cacheEntry{history: history, fetchedAt: c.now()}
+	c.mu.Unlock()
+}
+
+// fetch completes call once dss returns or fetchTimeout passes, whichever is
+// first, so that a hanging fetch does not block later ones. Services that
+// ignore ctx are left to finish in the background.
+func (c *HistoryCache) fetch(ctx context.Context, key string, dss DownloadStatsService, call *cacheCall) {
+	ctx, cancel := context.WithTimeout(ctx, c.fetchTimeout)
+	defer cancel()
+
+	done := make(chan struct{})
+	var history *ReleaseHistory
+	var err error
+	go func() {
+		history, err = fetchReleaseHistory(ctx, dss)
+		close(done)
+	}()
+	select {
+	case <-done:
+		call.history, call.err = history, err
+	case <-ctx.Done():
+		call.err = ctx.Err()
+	}
+
+	c.mu.Lock()
+	if call.err == nil {
+		c.entries[key] = &cacheEntry{history: call.history, fetchedAt: c.now()}
+	}
+	delete(c.calls, key)
+	c.mu.Unlock()
+	close(call.done)
+}
@@ -0,0 +1,161 @@
+package ghds
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingService is a DownloadStatsService that counts its fetches and
+// fails with err when it is set.
+type countingService struct {
+	history *ReleaseHistory
+	err     error
+	fetches int
+}
+
+func (s *countingService) FetchReleaseHistory() (*ReleaseHistory, error) {
+	s.fetches++
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.history, nil
+}
+
+func TestHistoryCache(t *testing.T) {
+	dss := &countingService{history: &ReleaseHistory{Repository: "foo/bar"}}
+	cache := NewHistoryCache(time.Minute, map[string]DownloadStatsService{"foo/bar": dss})
+	now := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+
+	for _, repository := range []string{"foo/bar", "Foo/Bar"} {
+		history, err := cache.Get(context.Background(), repository)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if history != dss.history {
+			t.Errorf("got %+v, expected %+v", history, dss.history)
+		}
+	}
+	if dss.fetches != 1 {
+		t.Errorf("expected 1 fetch within the TTL, got %d", dss.fetches)
+	}
+
+	now = now.Add(time.Minute)
+	if _, err := cache.Get(context.Background(), "foo/bar"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if dss.fetches != 2 {
+		t.Errorf("expected an expired history to be fetched again, got %d fetches", dss.fetches)
+	}
+
+	now = now.Add(time.Minute)
+	dss.err = errors.New("boom")
+	if history, err := cache.Get(context.Background(), "foo/bar"); err != nil || history != dss.history {
+		t.Errorf("expected the expired history when fetching fails, got %+v, %v", history, err)
+	}
+
+	if _, err := cache.Get(context.Background(), "foo/baz"); err != ErrUnknownRepository {
+		t.Errorf("expected ErrUnknownRepository, got %v", err)
+	}
+
+	empty := NewHistoryCache(time.Minute, map[string]DownloadStatsService{"foo/bar": dss})
+	if _, err := empty.Get(context.Background(), "foo/bar"); err == nil {
+		t.Errorf("expected an error when nothing is cached and fetching fails")
+	}
+}
+
+// blockingService is a DownloadStatsService whose fetches wait for release
+// to be closed.
+type blockingService struct {
+	history *ReleaseHistory
+	started chan struct{}
+	release chan struct{}
+	fetches int32
+}
+
+func (s *blockingService) FetchReleaseHistory() (*ReleaseHistory, error) {
+	if atomic.AddInt32(&s.fetches, 1) == 1 {
+		close(s.started)
+	}
+	<-s.release
+	return s.history, nil
+}
+
+func TestHistoryCacheSingleFlight(t *testing.T) {
+	dss := &blockingService{
+		history: &ReleaseHistory{Repository: "foo/bar"},
+		started: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+	cache := NewHistoryCache(time.Minute, map[string]DownloadStatsService{"foo/bar": dss})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelled := make(chan error)
+	go func() {
+		_, err := cache.Get(ctx, "foo/bar")
+		cancelled <- err
+	}()
+	<-dss.started
+
+	var wg sync.WaitGroup
+	histories := make([]*ReleaseHistory, 10)
+	for i := range histories {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			histories[i], _ = cache.Get(context.Background(), "foo/bar")
+		}(i)
+	}
+
+	cancel()
+	if err := <-cancelled; err != context.Canceled {
+		t.Errorf("expected context.Canceled for the cancelled request, got %v", err)
+	}
+
+	close(dss.release)
+	wg.Wait()
+	if fetches := atomic.LoadInt32(&dss.fetches); fetches != 1 {
+		t.Errorf("expected concurrent requests to share 1 fetch, got %d", fetches)
+	}
+	for i, history := range histories {
+		if history != dss.history {
+			t.Errorf("request %d: got %+v, expected %+v", i, history, dss.history)
+		}
+	}
+}
+
+func TestHistoryCacheFetchTimeout(t *testing.T) {
+	dss := &blockingService{
+		history: &ReleaseHistory{Repository: "foo/bar"},
+		started: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+	defer close(dss.release)
+	cache := NewHistoryCache(time.Minute, map[string]DownloadStatsService{"foo/bar": dss})
+	cache.fetchTimeout = 10 * time.Millisecond
+
+	for i := 0; i < 2; i++ {
+		if _, err := cache.Get(context.Background(), "foo/bar"); err != context.DeadlineExceeded {
+			t.Errorf("expected context.DeadlineExceeded, got %v", err)
+		}
+		cache.mu.Lock()
+		calls := len(cache.calls)
+		cache.mu.Unlock()
+		if calls != 0 {
+			t.Errorf("expected the timed out fetch to be cleared, got %d calls", calls)
+		}
+	}
+
+	// Each Get started its own fetch, which may still be starting.
+	deadline := time.Now().Add(time.Second)
+	for atomic.LoadInt32(&dss.fetches) < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if fetches := atomic.LoadInt32(&dss.fetches); fetches != 2 {
+		t.Errorf("expected a new fetch after the timeout, got %d fetches", fetches)
+	}
+}
@@ -0,0 +1,117 @@
+package ghds
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Config lists repositories to fetch in a single run. Settings in Defaults
+// apply to every repository unless the repository overrides them.
+type Config struct {
+	Defaults     RepositoryConfig   `json:"defaults"`
+	Repositories []RepositoryConfig `json:"repositories"`
+}
+
+// RepositoryConfig mirrors GitHubDownloadStatsOptions for a single repository.
+// The API token is read from the environment variable named by TokenEnv so
+// that it does not need to be stored in the config file.
+type RepositoryConfig struct {
+	Owner         string   `json:"owner,omitempty"`
+	Repo          string   `json:"repo,omitempty"`
+	Release       string   `json:"release,omitempty"`
+	Versions      string   `json:"versions,omitempty"`
+	IncludeAssets []string `json:"include_assets,omitempty"`
+	ExcludeAssets []string `json:"exclude_assets,omitempty"`
+	PreRelease    *bool    `json:"pre_release,omitempty"`
+	Drafts        *bool    `json:"drafts,omitempty"`
+	ApiEndpoint   string   `json:"api_endpoint,omitempty"`
+	TokenEnv      string   `json:"token_env,omitempty"`
+}
+
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &Config{}
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err)
+	}
+
+	if len(config.Repositories) == 0 {
+		return nil, fmt.Errorf("%s: no repositories configured", path)
+	}
+	if err := config.Defaults.validate(); err != nil {
+		return nil, fmt.Errorf("%s: defaults: %s", path, err)
+	}
+	for i, rc := range config.Repositories {
+		if rc.Owner == "" || rc.Repo == "" {
+			return nil, fmt.Errorf("%s: repository %d: must set the repo and owner", path, i+1)
+		}
+		if err := rc.validate(); err != nil {
+			return nil, fmt.Errorf("%s: repository %d: %s", path, i+1, err)
+		}
+	}
+
+	return config, nil
+}
+
+// validate checks the settings that are parsed when releases are fetched, so
+// that mistakes are reported before any requests are made.
+func (rc RepositoryConfig) validate() error {
+	if rc.Versions != "" {
+		if _, err := ParseConstraint(rc.Versions); err != nil {
+			return err
+		}
+	}
+	_, err := NewAssetFilter(rc.IncludeAssets, rc.ExcludeAssets)
+	return err
+}
+
+// Options returns the options for a configured repository, layering the
+// repository's settings over the config defaults over base.
+func (c *Config) Options(rc RepositoryConfig, base *GitHubDownloadStatsOptions) *GitHubDownloadStatsOptions {
+	options := *base
+	for _, layer := range []RepositoryConfig{c.Defaults, rc} {
+		if layer.Release != "" {
+			options.Release = layer.Release
+		}
+		if layer.Versions != "" {
+			options.Versions = layer.Versions
+		}
+		if layer.IncludeAssets != nil {
+			options.IncludeAssets = layer.IncludeAssets
+		}
+		if layer.ExcludeAssets != nil {
+			options.ExcludeAssets = layer.ExcludeAssets
+		}
+		if layer.PreRelease != nil {
+			options.PreRelease = *layer.PreRelease
+		}
+		if layer.Drafts != nil {
+			options.Drafts = *layer.Drafts
+		}
+		if layer.ApiEndpoint != "" {
+			options.ApiEndpoint = layer.ApiEndpoint
+		}
+		if layer.TokenEnv != "" {
+			options.Token = os.Getenv(layer.TokenEnv)
+		}
+	}
+	return &options
+}
+
+// Services returns a download stats service for every configured repository.
+func (c *Config) Services(base *GitHubDownloadStatsOptions, opts ...ServiceOption) ([]*GitHubDownloadStatsService, error) {
+	services := make([]*GitHubDownloadStatsService, 0, len(c.Repositories))
+	for _, rc := range c.Repositories {
+		dss, err := NewGitHubDownloadStatsService(rc.Owner, rc.Repo, c.Options(rc, base), opts...)
+		if err != nil {
+			return nil, fmt.Errorf("%s/%s: %w", rc.Owner, rc.Repo, err)
+		}
+		services = append(services, dss)
+	}
+	return services, nil
+}
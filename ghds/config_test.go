@@ -0,0 +1,69 @@
+package ghds
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+	t.Setenv("GHDS_TEST_TOKEN", "secret")
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	err := os.WriteFile(path, []byte(`{
+  "defaults": {"pre_release": true, "token_env": "GHDS_TEST_TOKEN"},
+  "repositories": [
+    {"owner": "digitalocean", "repo": "doctl"},
+    {"owner": "foo", "repo": "bar", "release": "v1.0.0", "pre_release": false, "api_endpoint": "https://ghe.example.com/api/v3/"}
+  ]
+}`), 0644)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	config, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	base := &GitHubDownloadStatsOptions{Format: FormatJSON, Token: "cli"}
+	expected := []*GitHubDownloadStatsOptions{
+		{Format: FormatJSON, Token: "secret", PreRelease: true},
+		{Format: FormatJSON, Token: "secret", Release: "v1.0.0", ApiEndpoint: "https://ghe.example.com/api/v3/"},
+	}
+	for i, rc := range config.Repositories {
+		actual := config.Options(rc, base)
+		if !reflect.DeepEqual(actual, expected[i]) {
+			t.Errorf("repository %d: got %+v, expected %+v", i, actual, expected[i])
+		}
+	}
+
+	services, err := config.Services(base)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(services) != 2 || services[1].Repository() != "foo/bar" {
+		t.Errorf("unexpected services: %v", services)
+	}
+}
+
+func TestLoadConfigInvalid(t *testing.T) {
+	var invalidConfigTests = []string{
+		`{"repositories": []}`,
+		`{"repositories": [{"owner": "foo"}]}`,
+		`{"repositories": {}}`,
+		`{"defaults": {"versions": ">=foo"}, "repositories": [{"owner": "foo", "repo": "bar"}]}`,
+		`{"repositories": [{"owner": "foo", "repo": "bar", "exclude_assets": ["re:("]}]}`,
+	}
+
+	for _, tt := range invalidConfigTests {
+		path := filepath.Join(t.TempDir(), "config.json")
+		if err := os.WriteFile(path, []byte(tt), 0644); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if _, err := LoadConfig(path); err == nil {
+			t.Errorf("LoadConfig(%s): expected error", tt)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package ghds
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strconv"
+	"time"
+)
+
+// formatHistoriesCSV flattens release histories into one row per asset, with
+// a header row. comma selects the field separator, e.g. '\t' for TSV.
+func formatHistoriesCSV(histories []*ReleaseHistory, comma rune) (string, error) {
+	buf := new(bytes.Buffer)
+	w := csv.NewWriter(buf)
+	w.Comma = comma
+
+	w.Write([]string{"repository", "release", "tag", "date", "asset", "downloads", "release_total_downloads", "prerelease", "draft"})
+	for _, history := range histories {
+		for _, rel := range history.Releases {
+			for _, asset := range rel.Assets {
+				w.Write([]string{
+					history.Repository,
+					rel.Name,
+					rel.Tag,
+					rel.Date.UTC().Format(time.RFC3339),
+					asset.Name,
+					strconv.Itoa(asset.Downloads),
+					strconv.Itoa(rel.TotalDownloads),
+					strconv.FormatBool(rel.Prerelease),
+					strconv.FormatBool(rel.Draft),
+				})
+			}
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// formatDiffCSV flattens a diff into one row per asset, with a header row.
+func formatDiffCSV(diff *HistoryDiff, comma rune) (string, error) {
+	buf := new(bytes.Buffer)
+	w := csv.NewWriter(buf)
+	w.Comma = comma
+
+	w.Write([]string{"repository", "release", "tag", "asset", "status", "before", "after", "delta"})
+	for _, rel := range diff.Releases {
+		for _, asset := range rel.Assets {
+			w.Write([]string{
+				diff.Repository,
+				rel.Name,
+				rel.Tag,
+				asset.Name,
+				asset.Status,
+				strconv.Itoa(asset.Before),
+				strconv.Itoa(asset.After),
+				strconv.Itoa(asset.Delta),
+			})
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// formatBreakdownCSV writes one row per platform value of each dimension.
+func formatBreakdownCSV(breakdown *PlatformBreakdown, comma rune) (string, error) {
+	buf := new(bytes.Buffer)
+	w := csv.NewWriter(buf)
+	w.Comma = comma
+
+	w.Write([]string{"dimension", "name", "assets", "downloads"})
+	for _, section := range []struct {
+		dimension string
+		counts    []PlatformCount
+	}{
+		{PlatformOS, breakdown.OS},
+		{PlatformArch, breakdown.Arch},
+		{PlatformPackage, breakdown.Package},
+	} {
+		for _, pc := range section.counts {
+			w.Write([]string{section.dimension, pc.Name, strconv.Itoa(pc.Assets), strconv.Itoa(pc.Downloads)})
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// formatLineageCSV writes one row per artifact per release.
+func formatLineageCSV(lineages []*AssetLineage, comma rune) (string, error) {
+	buf := new(bytes.Buffer)
+	w := csv.NewWriter(buf)
+	w.Comma = comma
+
+	w.Write([]string{"repository", "artifact", "release", "tag", "date", "asset", "downloads"})
+	for _, lineage := range lineages {
+		for _, artifact := range lineage.Artifacts {
+			for _, rel := range artifact.Releases {
+				w.Write([]string{
+					lineage.Repository,
+					artifact.Artifact,
+					rel.Release,
+					rel.Tag,
+					rel.Date.UTC().Format(time.RFC3339),
+					rel.Asset,
+					strconv.Itoa(rel.Downloads),
+				})
+			}
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// formatRollupCSV writes one row per version line, with the level being
+// "major", "minor" or "unversioned".
+func formatRollupCSV(rollups []*VersionRollup, comma rune) (string, error) {
+	buf := new(bytes.Buffer)
+	w := csv.NewWriter(buf)
+	w.Comma = comma
+
+	w.Write([]string{"repository", "level", "line", "releases", "downloads", "first_release", "last_release"})
+	write := func(repository string, level string, line VersionLine) {
+		w.Write([]string{
+			repository,
+			level,
+			line.Line,
+			strconv.Itoa(line.ReleaseCount),
+			strconv.Itoa(line.TotalDownloads),
+			line.FirstRelease.UTC().Format(time.RFC3339),
+			line.LastRelease.UTC().Format(time.RFC3339),
+		})
+	}
+	for _, rollup := range rollups {
+		for _, line := range rollup.Majors {
+			write(rollup.Repository, "major", line)
+		}
+		for _, line := range rollup.Minors {
+			write(rollup.Repository, "minor", line)
+		}
+		if rollup.Unversioned != nil {
+			write(rollup.Repository, "unversioned", *rollup.Unversioned)
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,180 @@
+package ghds
+
+import (
+	"strconv"
+	"time"
+)
+
+const (
+	DiffAdded     = "added"
+	DiffRemoved   = "removed"
+	DiffChanged   = "changed"
+	DiffUnchanged = "unchanged"
+)
+
+// HistoryDiff describes how the download counts of a repository changed
+// between two snapshots of its release history. Delta counts the downloads
+// since the older snapshot; removed releases and assets are left out of it
+// and their downloads totalled in Removed instead. The Delta and Removed of
+// each release follow the same rule, so they add up to the totals.
+type HistoryDiff struct {
+	Repository string        `json:"repository"`
+	From       time.Time     `json:"from"`
+	To         time.Time     `json:"to"`
+	Releases   []ReleaseDiff `json:"releases"`
+	Delta      int           `json:"delta"`
+	Removed    int           `json:"removed"`
+}
+
+type ReleaseDiff struct {
+	Name    string      `json:"name"`
+	Tag     string      `json:"tag_name"`
+	Status  string      `json:"status"`
+	Before  int         `json:"before"`
+	After   int         `json:"after"`
+	Delta   int         `json:"delta"`
+	Removed int         `json:"removed"`
+	Assets  []AssetDiff `json:"assets"`
+}
+
+// AssetDiff is the change in downloads of one asset. Removed assets have no
+// Delta; their downloads are counted in the Removed of their release.
+type AssetDiff struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Before int    `json:"before"`
+	After  int    `json:"after"`
+	Delta  int    `json:"delta"`
+}
+
+// Diff compares two snapshots of the same repository. Releases are matched by
+// tag name (or name if untagged) and assets by name. Releases and assets are
+// listed in the order of the newer snapshot, followed by any that were removed.
+func Diff(from Snapshot, to Snapshot) *HistoryDiff {
+	diff := &HistoryDiff{
+		From:     from.Timestamp,
+		To:       to.Timestamp,
+		Releases: []ReleaseDiff{},
+	}
+
+	var fromReleases, toReleases []Release
+	if from.History != nil {
+		diff.Repository = from.History.Repository
+		fromReleases = from.History.Releases
+	}
+	if to.History != nil {
+		diff.Repository = to.History.Repository
+		toReleases = to.History.Releases
+	}
+
+	previous := map[string]Release{}
+	for _, rel := range fromReleases {
+		previous[releaseKey(rel)] = rel
+	}
+
+	seen := map[string]bool{}
+	for _, rel := range toReleases {
+		key := releaseKey(rel)
+		seen[key] = true
+
+		old, ok := previous[key]
+		if !ok {
+			diff.Releases = append(diff.Releases, diffRelease(Release{}, rel, DiffAdded))
+			continue
+		}
+		diff.Releases = append(diff.Releases, diffRelease(old, rel, ""))
+	}
+
+	for _, rel := range fromReleases {
+		if !seen[releaseKey(rel)] {
+			diff.Releases = append(diff.Releases, diffRelease(rel, Release{Name: rel.Name, Tag: rel.Tag}, DiffRemoved))
+		}
+	}
+
+	for _, rd := range diff.Releases {
+		diff.Delta += rd.Delta
+		diff.Removed += rd.Removed
+	}
+
+	return diff
+}
+
+func diffRelease(from Release, to Release, status string) ReleaseDiff {
+	rd := ReleaseDiff{
+		Name:   to.Name,
+		Tag:    to.Tag,
+		Before: from.TotalDownloads,
+		After:  to.TotalDownloads,
+		Assets: []AssetDiff{},
+	}
+
+	previous := map[string]int{}
+	for _, a := range from.Assets {
+		previous[a.Name] = a.Downloads
+	}
+
+	changed := false
+	seen := map[string]bool{}
+	for _, a := range to.Assets {
+		seen[a.Name] = true
+		ad := AssetDiff{Name: a.Name, After: a.Downloads}
+		before, ok := previous[a.Name]
+		switch {
+		case !ok:
+			ad.Status = DiffAdded
+		case before != a.Downloads:
+			ad.Status = DiffChanged
+		default:
+			ad.Status = DiffUnchanged
+		}
+		ad.Before = before
+		ad.Delta = ad.After - ad.Before
+		rd.Delta += ad.Delta
+		changed = changed || ad.Status != DiffUnchanged
+		rd.Assets = append(rd.Assets, ad)
+	}
+
+	for _, a := range from.Assets {
+		if !seen[a.Name] {
+			rd.Assets = append(rd.Assets, AssetDiff{
+				Name:   a.Name,
+				Status: DiffRemoved,
+				Before: a.Downloads,
+			})
+			rd.Removed += a.Downloads
+			changed = true
+		}
+	}
+
+	switch {
+	case status != "":
+		rd.Status = status
+	case changed || rd.Delta != 0:
+		rd.Status = DiffChanged
+	default:
+		rd.Status = DiffUnchanged
+	}
+
+	return rd
+}
+
+// formatCount renders a download count with thousands separators, e.g. 1,203.
+func formatCount(n int) string {
+	s := strconv.Itoa(n)
+	sign := ""
+	if n < 0 {
+		sign, s = "-", s[1:]
+	}
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return sign + s
+}
+
+// formatDelta renders a change in downloads with an explicit sign, e.g. +1,203.
+func formatDelta(n int) string {
+	if n > 0 {
+		return "+" + formatCount(n)
+	}
+	return formatCount(n)
+}
@@ -0,0 +1,196 @@
+package ghds
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func diffTestSnapshots() (Snapshot, Snapshot) {
+	timeOne := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	timeTwo := time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)
+
+	from := Snapshot{
+		Timestamp: timeOne,
+		History: &ReleaseHistory{
+			Repository: "foo/bar",
+			Releases: []Release{
+				Release{
+					Name: "v1.0.0",
+					Tag:  "v1.0.0",
+					Assets: []ReleaseAsset{
+						ReleaseAsset{Name: "example.zip", Downloads: 42},
+						ReleaseAsset{Name: "example.tar.gz", Downloads: 10},
+					},
+					TotalDownloads: 52,
+				},
+				Release{
+					Name: "v0.9.0",
+					Tag:  "v0.9.0",
+					Assets: []ReleaseAsset{
+						ReleaseAsset{Name: "example.zip", Downloads: 5},
+					},
+					TotalDownloads: 5,
+				},
+			},
+			ReleaseCount: 2,
+		},
+	}
+
+	to := Snapshot{
+		Timestamp: timeTwo,
+		History: &ReleaseHistory{
+			Repository: "foo/bar",
+			Releases: []Release{
+				Release{
+					Name: "v2.0.0",
+					Tag:  "v2.0.0",
+					Assets: []ReleaseAsset{
+						ReleaseAsset{Name: "example.zip", Downloads: 1200},
+					},
+					TotalDownloads: 1200,
+				},
+				Release{
+					Name: "v1.0.0",
+					Tag:  "v1.0.0",
+					Assets: []ReleaseAsset{
+						ReleaseAsset{Name: "example.zip", Downloads: 45},
+						ReleaseAsset{Name: "example.deb", Downloads: 3},
+					},
+					TotalDownloads: 48,
+				},
+			},
+			ReleaseCount: 2,
+		},
+	}
+	return from, to
+}
+
+func TestDiff(t *testing.T) {
+	from, to := diffTestSnapshots()
+	timeOne, timeTwo := from.Timestamp, to.Timestamp
+
+	expected := &HistoryDiff{
+		Repository: "foo/bar",
+		From:       timeOne,
+		To:         timeTwo,
+		Releases: []ReleaseDiff{
+			{Name: "v2.0.0", Tag: "v2.0.0", Status: DiffAdded, After: 1200, Delta: 1200, Assets: []AssetDiff{
+				{Name: "example.zip", Status: DiffAdded, After: 1200, Delta: 1200},
+			}},
+			{Name: "v1.0.0", Tag: "v1.0.0", Status: DiffChanged, Before: 52, After: 48, Delta: 6, Removed: 10, Assets: []AssetDiff{
+				{Name: "example.zip", Status: DiffChanged, Before: 42, After: 45, Delta: 3},
+				{Name: "example.deb", Status: DiffAdded, After: 3, Delta: 3},
+				{Name: "example.tar.gz", Status: DiffRemoved, Before: 10},
+			}},
+			{Name: "v0.9.0", Tag: "v0.9.0", Status: DiffRemoved, Before: 5, Removed: 5, Assets: []AssetDiff{
+				{Name: "example.zip", Status: DiffRemoved, Before: 5},
+			}},
+		},
+		Delta:   1206,
+		Removed: 15,
+	}
+
+	actual := Diff(from, to)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("got %+v, expected %+v", actual, expected)
+	}
+}
+
+func TestFormatDelta(t *testing.T) {
+	var formatDeltaTests = []struct {
+		input    int
+		expected string
+	}{
+		{0, "0"},
+		{42, "+42"},
+		{1203, "+1,203"},
+		{-1234567, "-1,234,567"},
+		{100000, "+100,000"},
+	}
+
+	for _, tt := range formatDeltaTests {
+		actual := formatDelta(tt.input)
+		if actual != tt.expected {
+			t.Errorf("formatDelta(%d): expected %s, actual %s", tt.input, tt.expected, actual)
+		}
+	}
+}
+
+func TestFormatDiff(t *testing.T) {
+	diff := Diff(diffTestSnapshots())
+
+	var formatDiffTests = []struct {
+		format   string
+		expected string
+	}{
+		{FormatText, expectedDiffText},
+		{FormatCSV, expectedDiffCSV},
+		{FormatJSON, expectedDiffJSON},
+	}
+
+	for _, tt := range formatDiffTests {
+		actual, err := FormatDiff(diff, tt.format)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", tt.format, err)
+		}
+		if actual != tt.expected {
+			t.Errorf("%s: got:\n%s\nexpected:\n%s", tt.format, actual, tt.expected)
+		}
+	}
+}
+
+const expectedDiffText = `Repository: foo/bar
+From: 2024-01-01 00:00:00 +0000 UTC
+To:   2024-01-08 00:00:00 +0000 UTC
+
+Release: v2.0.0 (added)
+ 
+ Asset:        Before: After: Change:
+ + example.zip 0       1,200  +1,200
+
+Change: +1,200
+
+------------------------------------------
+Release: v1.0.0 (changed)
+ 
+ Asset:           Before: After: Change:
+ ~ example.zip    42      45     +3
+ + example.deb    0       3      +3
+ x example.tar.gz 10      0      -
+
+Change:  +6
+Removed: 10
+
+------------------------------------------
+Release: v0.9.0 (removed)
+ 
+ Asset:        Before: After: Change:
+ x example.zip 5       0      -
+
+Change:  0
+Removed: 5
+
+------------------------------------------
++1,206 downloads since 2024-01-01 00:00:00 +0000 UTC
+15 downloads of removed releases and assets
+`
+
+const expectedDiffCSV = `repository,release,tag,asset,status,before,after,delta
+foo/bar,v2.0.0,v2.0.0,example.zip,added,0,1200,1200
+foo/bar,v1.0.0,v1.0.0,example.zip,changed,42,45,3
+foo/bar,v1.0.0,v1.0.0,example.deb,added,0,3,3
+foo/bar,v1.0.0,v1.0.0,example.tar.gz,removed,10,0,0
+foo/bar,v0.9.0,v0.9.0,example.zip,removed,5,0,0
+`
+
+const expectedDiffJSON = `{"repository":"foo/bar","from":"2024-01-01T00:00:00Z","to":"2024-01-08T00:00:00Z","releases":[` +
+	`{"name":"v2.0.0","tag_name":"v2.0.0","status":"added","before":0,"after":1200,"delta":1200,"removed":0,"assets":[` +
+	`{"name":"example.zip","status":"added","before":0,"after":1200,"delta":1200}]},` +
+	`{"name":"v1.0.0","tag_name":"v1.0.0","status":"changed","before":52,"after":48,"delta":6,"removed":10,"assets":[` +
+	`{"name":"example.zip","status":"changed","before":42,"after":45,"delta":3},` +
+	`{"name":"example.deb","status":"added","before":0,"after":3,"delta":3},` +
+	`{"name":"example.tar.gz","status":"removed","before":10,"after":0,"delta":0}]},` +
+	`{"name":"v0.9.0","tag_name":"v0.9.0","status":"removed","before":5,"after":0,"delta":0,"removed":5,"assets":[` +
+	`{"name":"example.zip","status":"removed","before":5,"after":0,"delta":0}]}],` +
+	`"delta":1206,"removed":15}`
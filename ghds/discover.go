@@ -0,0 +1,91 @@
+package ghds
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/github"
+)
+
+// DiscoveryOptions selects the repositories of an organization or user.
+// Archived and forked repositories are skipped unless explicitly included.
+type DiscoveryOptions struct {
+	Org             string
+	User            string
+	IncludeArchived bool
+	IncludeForks    bool
+	ExcludePrivate  bool
+}
+
+// DiscoverRepositories lists the repositories of an organization or user and
+// returns a download stats service for each one that passes the filters.
+func DiscoverRepositories(discovery *DiscoveryOptions, options *GitHubDownloadStatsOptions, opts ...ServiceOption) ([]*GitHubDownloadStatsService, error) {
+	return DiscoverRepositoriesContext(context.Background(), discovery, options, opts...)
+}
+
+// DiscoverRepositoriesContext is like DiscoverRepositories, but lists the
+// repositories with ctx.
+func DiscoverRepositoriesContext(ctx context.Context, discovery *DiscoveryOptions, options *GitHubDownloadStatsOptions, opts ...ServiceOption) ([]*GitHubDownloadStatsService, error) {
+	if (discovery.Org == "") == (discovery.User == "") {
+		return nil, fmt.Errorf("must set exactly one of org or user")
+	}
+
+	client, err := newGitHubClient(options, opts...)
+	if err != nil {
+		return nil, err
+	}
+	opt := github.ListOptions{PerPage: 100}
+	services := []*GitHubDownloadStatsService{}
+
+	for {
+		var repos []*github.Repository
+		resp, err := retry(ctx, options, func() (*github.Response, error) {
+			var (
+				resp *github.Response
+				err  error
+			)
+			if discovery.Org != "" {
+				repos, resp, err = client.Repositories.ListByOrg(ctx, discovery.Org,
+					&github.RepositoryListByOrgOptions{Type: "all", ListOptions: opt})
+			} else {
+				repos, resp, err = client.Repositories.List(ctx, discovery.User,
+					&github.RepositoryListOptions{Type: "owner", ListOptions: opt})
+			}
+			return resp, err
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range repos {
+			if includeGitHubRepository(r, discovery) {
+				services = append(services, &GitHubDownloadStatsService{
+					owner:   r.GetOwner().GetLogin(),
+					repo:    r.GetName(),
+					client:  client,
+					options: options,
+				})
+			}
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+
+	return services, nil
+}
+
+func includeGitHubRepository(r *github.Repository, discovery *DiscoveryOptions) bool {
+	if r.GetArchived() && !discovery.IncludeArchived {
+		return false
+	}
+	if r.GetFork() && !discovery.IncludeForks {
+		return false
+	}
+	if r.GetPrivate() && discovery.ExcludePrivate {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,72 @@
+package ghds
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestDiscoverRepositories(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/orgs/foo/repos", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[
+  {"name": "bar", "owner": {"login": "foo"}},
+  {"name": "archived", "owner": {"login": "foo"}, "archived": true},
+  {"name": "fork", "owner": {"login": "foo"}, "fork": true},
+  {"name": "private", "owner": {"login": "foo"}, "private": true}
+]`)
+	})
+
+	var discoverTests = []struct {
+		discovery *DiscoveryOptions
+		expected  []string
+	}{
+		{&DiscoveryOptions{Org: "foo"}, []string{"foo/bar", "foo/private"}},
+		{&DiscoveryOptions{Org: "foo", ExcludePrivate: true}, []string{"foo/bar"}},
+		{&DiscoveryOptions{Org: "foo", IncludeArchived: true, IncludeForks: true},
+			[]string{"foo/bar", "foo/archived", "foo/fork", "foo/private"}},
+	}
+
+	for _, tt := range discoverTests {
+		services, err := DiscoverRepositories(tt.discovery, options)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		actual := []string{}
+		for _, dss := range services {
+			actual = append(actual, dss.Repository())
+		}
+		if !reflect.DeepEqual(actual, tt.expected) {
+			t.Errorf("DiscoverRepositories(%+v): expected %v, actual %v", tt.discovery, tt.expected, actual)
+		}
+	}
+
+	if _, err := DiscoverRepositories(&DiscoveryOptions{Org: "foo", User: "foo"}, options); err == nil {
+		t.Errorf("expected error when setting both org and user")
+	}
+}
+
+func TestReportRank(t *testing.T) {
+	report := NewReport([]*ReleaseHistory{
+		&ReleaseHistory{Repository: "foo/few", Releases: []Release{{TotalDownloads: 5}}, ReleaseCount: 1},
+		&ReleaseHistory{Repository: "foo/none", Releases: []Release{}, ReleaseCount: 0},
+		&ReleaseHistory{Repository: "foo/many", Releases: []Release{{TotalDownloads: 50}, {TotalDownloads: 7}}, ReleaseCount: 2},
+	})
+	report.Rank()
+
+	actual := []string{}
+	for _, history := range report.Repositories {
+		actual = append(actual, history.Repository)
+	}
+	expected := []string{"foo/many", "foo/few"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("got %v, expected %v", actual, expected)
+	}
+	if report.RepositoryCount != 2 || report.TotalDownloads != 62 {
+		t.Errorf("unexpected totals: %+v", report)
+	}
+}
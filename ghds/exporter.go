@@ -0,0 +1,196 @@
+package ghds
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/github"
+)
+
+// rateReporter is implemented by services that can report the GitHub API rate
+// limit returned with their most recent response.
+type rateReporter interface {
+	Rate() github.Rate
+}
+
+type exporterTarget struct {
+	dss         DownloadStatsService
+	history     *ReleaseHistory
+	lastSuccess time.Time
+	errors      int
+}
+
+// Exporter periodically fetches the release history of one or more
+// repositories and serves the download counts in the Prometheus text
+// exposition format.
+type Exporter struct {
+	interval time.Duration
+	targets  []*exporterTarget
+	rate     *github.Rate
+	cache    *HistoryCache
+	mu       sync.RWMutex
+}
+
+// DefaultExporterInterval is how often an Exporter refreshes when it is given
+// no positive interval.
+const DefaultExporterInterval = 15 * time.Minute
+
+func NewExporter(interval time.Duration, services ...DownloadStatsService) *Exporter {
+	if interval <= 0 {
+		interval = DefaultExporterInterval
+	}
+
+	targets := make([]*exporterTarget, 0, len(services))
+	for _, dss := range services {
+		targets = append(targets, &exporterTarget{dss: dss})
+	}
+
+	return &Exporter{
+		interval: interval,
+		targets:  targets,
+	}
+}
+
+// Populate stores every release history the exporter fetches in cache, so
+// that the cache does not fetch the same repositories again.
+func (e *Exporter) Populate(cache *HistoryCache) {
+	e.mu.Lock()
+	e.cache = cache
+	e.mu.Unlock()
+}
+
+// Refresh fetches the release history of every repository once. Failed
+// fetches are counted and the previously fetched history is kept.
+func (e *Exporter) Refresh() {
+	e.RefreshContext(context.Background())
+}
+
+// RefreshContext is like Refresh, but fetches with ctx. Once ctx is done the
+// remaining repositories are skipped and not counted as failed.
+func (e *Exporter) RefreshContext(ctx context.Context) {
+	for _, target := range e.targets {
+		history, err := fetchReleaseHistory(ctx, target.dss)
+		if ctx.Err() != nil {
+			return
+		}
+
+		e.mu.Lock()
+		if err != nil {
+			target.errors++
+		} else {
+			target.history = history
+			target.lastSuccess = time.Now()
+		}
+		if rr, ok := target.dss.(rateReporter); ok {
+			if rate := rr.Rate(); rate.Limit > 0 {
+				e.rate = &rate
+			}
+		}
+		cache := e.cache
+		e.mu.Unlock()
+
+		if err == nil && cache != nil {
+			cache.Put(history)
+		}
+	}
+}
+
+// Run refreshes immediately and then on every interval until ctx is done.
+func (e *Exporter) Run(ctx context.Context) {
+	e.RefreshContext(ctx)
+
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			e.RefreshContext(ctx)
+		}
+	}
+}
+
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	fmt.Fprint(w, e.metrics())
+}
+
+func (e *Exporter) metrics() string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	buf := new(bytes.Buffer)
+
+	writeMetricHeader(buf, "github_release_asset_downloads_total", "gauge",
+		"Number of downloads of a GitHub release asset.")
+	lines := []string{}
+	for _, target := range e.targets {
+		if target.history == nil {
+			continue
+		}
+		for _, rel := range target.history.Releases {
+			for _, asset := range rel.Assets {
+				lines = append(lines, fmt.Sprintf("github_release_asset_downloads_total{%s} %d\n",
+					formatLabels("repository", target.history.Repository, "release", rel.Name,
+						"tag", rel.Tag, "asset", asset.Name),
+					asset.Downloads))
+			}
+		}
+	}
+	sort.Strings(lines)
+	buf.WriteString(strings.Join(lines, ""))
+
+	writeMetricHeader(buf, "github_release_downloads_last_success_timestamp_seconds", "gauge",
+		"Unix time of the last successful fetch of a repository's release history.")
+	for _, target := range e.targets {
+		if target.history == nil {
+			continue
+		}
+		fmt.Fprintf(buf, "github_release_downloads_last_success_timestamp_seconds{%s} %d\n",
+			formatLabels("repository", target.history.Repository), target.lastSuccess.Unix())
+	}
+
+	writeMetricHeader(buf, "github_release_downloads_api_errors_total", "counter",
+		"Number of failed fetches of release history from the GitHub API.")
+	errors := 0
+	for _, target := range e.targets {
+		errors += target.errors
+	}
+	fmt.Fprintf(buf, "github_release_downloads_api_errors_total %d\n", errors)
+
+	if e.rate != nil {
+		writeMetricHeader(buf, "github_release_downloads_rate_limit_remaining", "gauge",
+			"Number of GitHub API requests remaining in the current rate limit window.")
+		fmt.Fprintf(buf, "github_release_downloads_rate_limit_remaining %d\n", e.rate.Remaining)
+
+		writeMetricHeader(buf, "github_release_downloads_rate_limit", "gauge",
+			"Number of GitHub API requests permitted per rate limit window.")
+		fmt.Fprintf(buf, "github_release_downloads_rate_limit %d\n", e.rate.Limit)
+	}
+
+	return buf.String()
+}
+
+func writeMetricHeader(buf *bytes.Buffer, name string, kind string, help string) {
+	fmt.Fprintf(buf, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(buf, "# TYPE %s %s\n", name, kind)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// formatLabels renders alternating label names and values as a Prometheus label set.
+func formatLabels(pairs ...string) string {
+	labels := make([]string, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		labels = append(labels, fmt.Sprintf(`%s="%s"`, pairs[i], labelEscaper.Replace(pairs[i+1])))
+	}
+	return strings.Join(labels, ",")
+}
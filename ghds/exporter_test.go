@@ -0,0 +1,94 @@
+package ghds
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExporter(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/repos/foo/bar/releases", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "4321")
+		fmt.Fprint(w, `[
+  {
+    "tag_name": "v1.0.0",
+    "name": "First \"stable\" release",
+    "created_at": "2013-02-27T19:35:32Z",
+    "assets": [
+      {
+        "name": "example.zip",
+        "download_count": 42
+      }
+    ]
+  }
+]`)
+	})
+	mux.HandleFunc("/repos/foo/missing/releases", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message": "Not Found"}`, http.StatusNotFound)
+	})
+
+	exporter := NewExporter(0,
+		newTestService(t, "foo", "bar", options),
+		newTestService(t, "foo", "missing", options),
+	)
+	exporter.Refresh()
+
+	rec := httptest.NewRecorder()
+	exporter.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+
+	expectedLines := []string{
+		`github_release_asset_downloads_total{repository="foo/bar",release="First \"stable\" release",tag="v1.0.0",asset="example.zip"} 42`,
+		`# TYPE github_release_downloads_api_errors_total counter`,
+		`github_release_downloads_api_errors_total 1`,
+		`github_release_downloads_rate_limit_remaining 4321`,
+	}
+	for _, line := range expectedLines {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("expected metrics to contain %q, got:\n%s", line, body)
+		}
+	}
+
+	if strings.Contains(body, `repository="foo/missing"`) {
+		t.Errorf("expected no metrics for failed repository, got:\n%s", body)
+	}
+}
+
+func TestExporterDefaultInterval(t *testing.T) {
+	exporter := NewExporter(0)
+	if exporter.interval != DefaultExporterInterval {
+		t.Errorf("expected interval %v, got %v", DefaultExporterInterval, exporter.interval)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	exporter.Run(ctx)
+}
+
+func TestExporterPopulate(t *testing.T) {
+	dss := &countingService{history: &ReleaseHistory{Repository: "foo/bar"}}
+	cache := NewHistoryCache(time.Minute, map[string]DownloadStatsService{"foo/bar": dss})
+
+	exporter := NewExporter(0, dss)
+	exporter.Populate(cache)
+	exporter.Refresh()
+
+	history, err := cache.Get(context.Background(), "foo/bar")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if history != dss.history {
+		t.Errorf("got %+v, expected %+v", history, dss.history)
+	}
+	if dss.fetches != 1 {
+		t.Errorf("expected the cache to use the exporter's fetch, got %d fetches", dss.fetches)
+	}
+}
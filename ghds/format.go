@@ -0,0 +1,531 @@
+package ghds
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+)
+
+const (
+	FormatText     = "text"
+	FormatJSON     = "json"
+	FormatCSV      = "csv"
+	FormatTSV      = "tsv"
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+	FormatSVGBadge = "svg-badge"
+	FormatSVGChart = "svg-chart"
+)
+
+// Formatter renders a release history, e.g. as text or JSON.
+type Formatter interface {
+	Format(history *ReleaseHistory) (string, error)
+}
+
+// ReportFormatter is implemented by formatters that can render a report
+// combining several repositories.
+type ReportFormatter interface {
+	FormatReport(report *Report) (string, error)
+}
+
+// DiffFormatter is implemented by formatters that can render a diff between
+// two snapshots.
+type DiffFormatter interface {
+	FormatDiff(diff *HistoryDiff) (string, error)
+}
+
+// BreakdownFormatter is implemented by formatters that can render downloads
+// aggregated by platform.
+type BreakdownFormatter interface {
+	FormatBreakdown(breakdown *PlatformBreakdown) (string, error)
+}
+
+// LineageFormatter is implemented by formatters that can render the download
+// history of artifacts across releases.
+type LineageFormatter interface {
+	FormatLineage(lineages []*AssetLineage) (string, error)
+}
+
+// RollupFormatter is implemented by formatters that can render downloads
+// summed by version line.
+type RollupFormatter interface {
+	FormatRollup(rollups []*VersionRollup) (string, error)
+}
+
+// FormatterFunc adapts an ordinary function to the Formatter interface.
+type FormatterFunc func(history *ReleaseHistory) (string, error)
+
+func (f FormatterFunc) Format(history *ReleaseHistory) (string, error) {
+	return f(history)
+}
+
+var (
+	formatters   = map[string]Formatter{}
+	formattersMu sync.RWMutex
+)
+
+func init() {
+	RegisterFormatter(FormatText, textFormatter{})
+	RegisterFormatter(FormatJSON, jsonFormatter{})
+	RegisterFormatter(FormatCSV, csvFormatter{comma: ','})
+	RegisterFormatter(FormatTSV, csvFormatter{comma: '\t'})
+	RegisterFormatter(FormatMarkdown, markdownFormatter{})
+	RegisterFormatter(FormatHTML, NewHTMLFormatter(nil))
+	RegisterFormatter(FormatSVGBadge, NewBadgeFormatter(""))
+	RegisterFormatter(FormatSVGChart, FormatterFunc(func(history *ReleaseHistory) (string, error) {
+		return ReleaseChartSVG(history), nil
+	}))
+}
+
+// RegisterFormatter makes a formatter available by name, replacing any
+// formatter previously registered with that name.
+func RegisterFormatter(name string, f Formatter) {
+	formattersMu.Lock()
+	defer formattersMu.Unlock()
+	formatters[name] = f
+}
+
+// LookupFormatter returns the formatter registered with name. An empty name
+// selects the text formatter.
+func LookupFormatter(name string) (Formatter, error) {
+	if name == "" {
+		name = FormatText
+	}
+
+	formattersMu.RLock()
+	defer formattersMu.RUnlock()
+	f, ok := formatters[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown format %q", name)
+	}
+	return f, nil
+}
+
+// Formatters returns the names of all registered formatters, sorted.
+func Formatters() []string {
+	formattersMu.RLock()
+	defer formattersMu.RUnlock()
+
+	names := make([]string, 0, len(formatters))
+	for name := range formatters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func FormatHistory(history *ReleaseHistory, format string) (string, error) {
+	f, err := LookupFormatter(format)
+	if err != nil {
+		return "", err
+	}
+	return f.Format(history)
+}
+
+// FormatReport renders a report of several repositories followed by the
+// combined total of their downloads.
+func FormatReport(report *Report, format string) (string, error) {
+	f, err := LookupFormatter(format)
+	if err != nil {
+		return "", err
+	}
+	rf, ok := f.(ReportFormatter)
+	if !ok {
+		return "", fmt.Errorf("format %q does not support reports of several repositories", format)
+	}
+	return rf.FormatReport(report)
+}
+
+func FormatDiff(diff *HistoryDiff, format string) (string, error) {
+	f, err := LookupFormatter(format)
+	if err != nil {
+		return "", err
+	}
+	df, ok := f.(DiffFormatter)
+	if !ok {
+		return "", fmt.Errorf("format %q does not support diffs", format)
+	}
+	return df.FormatDiff(diff)
+}
+
+func FormatBreakdown(breakdown *PlatformBreakdown, format string) (string, error) {
+	f, err := LookupFormatter(format)
+	if err != nil {
+		return "", err
+	}
+	bf, ok := f.(BreakdownFormatter)
+	if !ok {
+		return "", fmt.Errorf("format %q does not support platform breakdowns", format)
+	}
+	return bf.FormatBreakdown(breakdown)
+}
+
+func FormatLineage(lineages []*AssetLineage, format string) (string, error) {
+	f, err := LookupFormatter(format)
+	if err != nil {
+		return "", err
+	}
+	lf, ok := f.(LineageFormatter)
+	if !ok {
+		return "", fmt.Errorf("format %q does not support asset lineage", format)
+	}
+	return lf.FormatLineage(lineages)
+}
+
+func FormatRollup(rollups []*VersionRollup, format string) (string, error) {
+	f, err := LookupFormatter(format)
+	if err != nil {
+		return "", err
+	}
+	rf, ok := f.(RollupFormatter)
+	if !ok {
+		return "", fmt.Errorf("format %q does not support version rollups", format)
+	}
+	return rf.FormatRollup(rollups)
+}
+
+type textFormatter struct{}
+
+func (textFormatter) Format(history *ReleaseHistory) (string, error) {
+	return formatHistoryText(history), nil
+}
+
+func (textFormatter) FormatReport(report *Report) (string, error) {
+	return formatReportText(report), nil
+}
+
+func (textFormatter) FormatDiff(diff *HistoryDiff) (string, error) {
+	return formatDiffText(diff), nil
+}
+
+func (textFormatter) FormatBreakdown(breakdown *PlatformBreakdown) (string, error) {
+	return formatBreakdownText(breakdown), nil
+}
+
+func (textFormatter) FormatLineage(lineages []*AssetLineage) (string, error) {
+	return formatLineageText(lineages), nil
+}
+
+func (textFormatter) FormatRollup(rollups []*VersionRollup) (string, error) {
+	return formatRollupText(rollups), nil
+}
+
+type jsonFormatter struct{}
+
+func (jsonFormatter) Format(history *ReleaseHistory) (string, error) {
+	return formatJSON(history)
+}
+
+func (jsonFormatter) FormatReport(report *Report) (string, error) {
+	return formatJSON(report)
+}
+
+func (jsonFormatter) FormatDiff(diff *HistoryDiff) (string, error) {
+	return formatJSON(diff)
+}
+
+func (jsonFormatter) FormatBreakdown(breakdown *PlatformBreakdown) (string, error) {
+	return formatJSON(breakdown)
+}
+
+func (jsonFormatter) FormatLineage(lineages []*AssetLineage) (string, error) {
+	return formatJSON(lineages)
+}
+
+func (jsonFormatter) FormatRollup(rollups []*VersionRollup) (string, error) {
+	return formatJSON(rollups)
+}
+
+type csvFormatter struct {
+	comma rune
+}
+
+func (f csvFormatter) Format(history *ReleaseHistory) (string, error) {
+	return formatHistoriesCSV([]*ReleaseHistory{history}, f.comma)
+}
+
+func (f csvFormatter) FormatReport(report *Report) (string, error) {
+	return formatHistoriesCSV(report.Repositories, f.comma)
+}
+
+func (f csvFormatter) FormatDiff(diff *HistoryDiff) (string, error) {
+	return formatDiffCSV(diff, f.comma)
+}
+
+func (f csvFormatter) FormatBreakdown(breakdown *PlatformBreakdown) (string, error) {
+	return formatBreakdownCSV(breakdown, f.comma)
+}
+
+func (f csvFormatter) FormatLineage(lineages []*AssetLineage) (string, error) {
+	return formatLineageCSV(lineages, f.comma)
+}
+
+func (f csvFormatter) FormatRollup(rollups []*VersionRollup) (string, error) {
+	return formatRollupCSV(rollups, f.comma)
+}
+
+func formatJSON(v interface{}) (string, error) {
+	obj, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+
+	return string(obj), nil
+}
+
+func formatHistoryText(history *ReleaseHistory) string {
+	buf := new(bytes.Buffer)
+	w := tabwriter.NewWriter(buf, 0, 4, 1, ' ', tabwriter.TabIndent)
+	fmt.Fprintf(w, "Repository: %s\n\n", history.Repository)
+	if len(history.Releases) > 0 {
+		for _, rel := range history.Releases {
+			fmt.Fprintf(w, "Release: %v%v\tDate: %v\n", rel.Name, releaseMarker(rel), rel.Date)
+			if rel.Tag != "" || rel.PublishedAt != nil {
+				published := "-"
+				if rel.PublishedAt != nil {
+					published = rel.PublishedAt.String()
+				}
+				fmt.Fprintf(w, "Tag: %v\tPublished: %v\n", rel.Tag, published)
+			}
+			if rel.Author != "" || rel.URL != "" {
+				fmt.Fprintf(w, "Author: %v\tURL: %v\n", rel.Author, rel.URL)
+			}
+			fmt.Fprintln(w, " ")
+
+			sizes := false
+			for _, asset := range rel.Assets {
+				sizes = sizes || asset.Size > 0
+			}
+			if sizes {
+				fmt.Fprintf(w, " Asset:\tDownloads:\tSize:\n")
+			} else {
+				fmt.Fprintf(w, " Asset:\tDownloads:\n")
+			}
+
+			for _, asset := range rel.Assets {
+				if sizes {
+					fmt.Fprintf(w, " - %v\t %v\t%v\n", asset.Name, asset.Downloads, formatSize(asset.Size))
+				} else {
+					fmt.Fprintf(w, " - %v\t\t%v\n", asset.Name, asset.Downloads)
+				}
+			}
+
+			fmt.Fprintf(w, "\nTotal downloads:\t%v\n\n", rel.TotalDownloads)
+			fmt.Fprintf(w, "------------------------------------------\n")
+			w.Flush()
+		}
+	}
+	w.Flush()
+
+	return buf.String()
+}
+
+func formatReportText(report *Report) string {
+	buf := new(bytes.Buffer)
+	for _, history := range report.Repositories {
+		fmt.Fprintf(buf, "%s\n", formatHistoryText(history))
+	}
+
+	w := tabwriter.NewWriter(buf, 0, 4, 1, ' ', tabwriter.TabIndent)
+	fmt.Fprintf(w, " Repository:\tDownloads:\n")
+	for _, history := range report.Repositories {
+		fmt.Fprintf(w, " - %v\t\t%v\n", history.Repository, history.TotalDownloads())
+	}
+	fmt.Fprintln(w, "")
+	fmt.Fprintf(w, "Repositories:\t%v\n", report.RepositoryCount)
+	fmt.Fprintf(w, "Grand total downloads:\t%v\n", report.TotalDownloads)
+	w.Flush()
+
+	return buf.String()
+}
+
+func formatDiffText(diff *HistoryDiff) string {
+	buf := new(bytes.Buffer)
+	w := tabwriter.NewWriter(buf, 0, 4, 1, ' ', tabwriter.TabIndent)
+	fmt.Fprintf(w, "Repository: %s\n", diff.Repository)
+	fmt.Fprintf(w, "From: %v\nTo:   %v\n\n", diff.From, diff.To)
+	for _, rel := range diff.Releases {
+		if rel.Status == DiffUnchanged {
+			continue
+		}
+		fmt.Fprintf(w, "Release: %v\t(%v)\n", rel.Name, rel.Status)
+		fmt.Fprintln(w, " ")
+		fmt.Fprintf(w, " Asset:\tBefore:\tAfter:\tChange:\n")
+
+		for _, asset := range rel.Assets {
+			if asset.Status == DiffUnchanged {
+				continue
+			}
+			change := formatDelta(asset.Delta)
+			if asset.Status == DiffRemoved {
+				change = "-"
+			}
+			fmt.Fprintf(w, " %v %v\t%v\t%v\t%v\n", diffMarker(asset.Status), asset.Name,
+				formatCount(asset.Before), formatCount(asset.After), change)
+		}
+
+		fmt.Fprintf(w, "\nChange:\t%v\n", formatDelta(rel.Delta))
+		if rel.Removed > 0 {
+			fmt.Fprintf(w, "Removed:\t%v\n", formatCount(rel.Removed))
+		}
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "------------------------------------------\n")
+		w.Flush()
+	}
+	fmt.Fprintf(w, "%v downloads since %v\n", formatDelta(diff.Delta), diff.From)
+	if diff.Removed > 0 {
+		fmt.Fprintf(w, "%v downloads of removed releases and assets\n", formatCount(diff.Removed))
+	}
+	w.Flush()
+
+	return buf.String()
+}
+
+func formatBreakdownText(breakdown *PlatformBreakdown) string {
+	buf := new(bytes.Buffer)
+	w := tabwriter.NewWriter(buf, 0, 4, 1, ' ', tabwriter.TabIndent)
+	if len(breakdown.Repositories) == 1 {
+		fmt.Fprintf(w, "Repository: %s\n\n", breakdown.Repositories[0])
+	} else {
+		fmt.Fprintf(w, "Repositories: %s\n\n", strings.Join(breakdown.Repositories, ", "))
+	}
+
+	sections := []struct {
+		title  string
+		counts []PlatformCount
+	}{
+		{"OS", breakdown.OS},
+		{"Architecture", breakdown.Arch},
+		{"Package", breakdown.Package},
+	}
+	for _, section := range sections {
+		fmt.Fprintf(w, " %s:\tAssets:\tDownloads:\tShare:\n", section.title)
+		for _, pc := range section.counts {
+			fmt.Fprintf(w, " - %v\t%v\t%v\t%v\n", pc.Name, pc.Assets, formatCount(pc.Downloads),
+				formatShare(pc.Downloads, breakdown.TotalDownloads))
+		}
+		fmt.Fprintln(w, "")
+		w.Flush()
+	}
+
+	fmt.Fprintf(w, "Total downloads:\t%v\n", formatCount(breakdown.TotalDownloads))
+	w.Flush()
+
+	return buf.String()
+}
+
+func formatLineageText(lineages []*AssetLineage) string {
+	buf := new(bytes.Buffer)
+	w := tabwriter.NewWriter(buf, 0, 4, 1, ' ', tabwriter.TabIndent)
+	for _, lineage := range lineages {
+		fmt.Fprintf(w, "Repository: %s\n\n", lineage.Repository)
+		for _, artifact := range lineage.Artifacts {
+			fmt.Fprintf(w, "Artifact: %v\n", artifact.Artifact)
+			fmt.Fprintln(w, " ")
+			fmt.Fprintf(w, " Release:\tDate:\tDownloads:\tChange:\n")
+
+			for i, rel := range artifact.Releases {
+				change := ""
+				if i > 0 {
+					change = formatDelta(rel.Downloads - artifact.Releases[i-1].Downloads)
+				}
+				fmt.Fprintf(w, " - %v\t%v\t%v\t%v\n", releaseKey(Release{Name: rel.Release, Tag: rel.Tag}),
+					rel.Date.Format("2006-01-02"), formatCount(rel.Downloads), change)
+			}
+
+			fmt.Fprintf(w, "\nTotal downloads:\t%v\n\n", formatCount(artifact.TotalDownloads))
+			fmt.Fprintf(w, "------------------------------------------\n")
+			w.Flush()
+		}
+	}
+	w.Flush()
+
+	return buf.String()
+}
+
+func formatRollupText(rollups []*VersionRollup) string {
+	buf := new(bytes.Buffer)
+	w := tabwriter.NewWriter(buf, 0, 4, 1, ' ', tabwriter.TabIndent)
+	for _, rollup := range rollups {
+		fmt.Fprintf(w, "Repository: %s\n\n", rollup.Repository)
+
+		sections := []struct {
+			title string
+			lines []VersionLine
+		}{
+			{"Major", rollup.Majors},
+			{"Minor", rollup.Minors},
+		}
+		if rollup.Unversioned != nil {
+			sections = append(sections, struct {
+				title string
+				lines []VersionLine
+			}{"Other", []VersionLine{*rollup.Unversioned}})
+		}
+		for _, section := range sections {
+			fmt.Fprintf(w, " %s:\tReleases:\tDownloads:\tShare:\tFirst release:\tLast release:\n", section.title)
+			for _, line := range section.lines {
+				fmt.Fprintf(w, " - %v\t%v\t%v\t%v\t%v\t%v\n", line.Line, line.ReleaseCount, formatCount(line.TotalDownloads),
+					formatShare(line.TotalDownloads, rollup.TotalDownloads),
+					line.FirstRelease.Format("2006-01-02"), line.LastRelease.Format("2006-01-02"))
+			}
+			fmt.Fprintln(w, "")
+			w.Flush()
+		}
+
+		fmt.Fprintf(w, "Total downloads:\t%v\n\n", formatCount(rollup.TotalDownloads))
+		fmt.Fprintf(w, "------------------------------------------\n")
+		w.Flush()
+	}
+
+	return buf.String()
+}
+
+// formatShare renders part as a percentage of total, e.g. 92.7%.
+func formatShare(part int, total int) string {
+	if total == 0 {
+		return "0.0%"
+	}
+	return fmt.Sprintf("%.1f%%", float64(part)*100/float64(total))
+}
+
+// releaseMarker flags pre-releases and drafts next to a release's name.
+func releaseMarker(rel Release) string {
+	switch {
+	case rel.Draft:
+		return " (draft)"
+	case rel.Prerelease:
+		return " (pre-release)"
+	default:
+		return ""
+	}
+}
+
+// formatSize renders a size in bytes using binary units, e.g. 1.5 MiB.
+func formatSize(bytes int) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := unit, 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
+func diffMarker(status string) string {
+	switch status {
+	case DiffAdded:
+		return "+"
+	case DiffRemoved:
+		return "x"
+	default:
+		return "~"
+	}
+}
@@ -0,0 +1,62 @@
+package ghds
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestFormatterRegistry(t *testing.T) {
+	for _, name := range []string{FormatText, FormatJSON, FormatCSV, FormatTSV} {
+		if _, err := LookupFormatter(name); err != nil {
+			t.Errorf("expected built-in formatter %q: %s", name, err)
+		}
+	}
+
+	if _, err := LookupFormatter("missing"); err == nil {
+		t.Errorf("expected error for unknown formatter")
+	}
+
+	RegisterFormatter("test-count", FormatterFunc(func(history *ReleaseHistory) (string, error) {
+		return strconv.Itoa(history.TotalDownloads()), nil
+	}))
+	defer func() {
+		formattersMu.Lock()
+		delete(formatters, "test-count")
+		formattersMu.Unlock()
+	}()
+
+	names := Formatters()
+	expected := []string{FormatCSV, FormatHTML, FormatJSON, FormatMarkdown, FormatSVGBadge, FormatSVGChart, "test-count", FormatText, FormatTSV}
+	if !reflect.DeepEqual(names[:len(expected)], expected) {
+		t.Errorf("got %v, expected %v", names, expected)
+	}
+
+	t.Run("build selects a registered formatter", func(t *testing.T) {
+		setup()
+		defer teardown()
+
+		mux.HandleFunc("/repos/foo/bar/releases", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `[{"tag_name": "v1.0.0", "assets": [{"name": "a.zip", "download_count": 40}, {"name": "b.zip", "download_count": 2}]}]`)
+		})
+
+		actual, err := Build(newTestService(t, "foo", "bar", options), "test-count")
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if actual != "42" {
+			t.Errorf("got %v, expected 42", actual)
+		}
+	})
+
+	t.Run("optional capabilities are checked", func(t *testing.T) {
+		if _, err := FormatReport(NewReport(nil), "test-count"); err == nil {
+			t.Errorf("expected error rendering a report with a history-only formatter")
+		}
+		if _, err := FormatDiff(&HistoryDiff{}, "test-count"); err == nil {
+			t.Errorf("expected error rendering a diff with a history-only formatter")
+		}
+	})
+}
@@ -1,12 +1,10 @@
 package ghds
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
-	"net/url"
-	"text/tabwriter"
+	"net/http"
+	"sync"
 	"time"
 
 	"github.com/google/go-github/github"
@@ -20,28 +18,80 @@ type ReleaseHistory struct {
 }
 
 type ReleaseAsset struct {
-	Name      string `json:"name"`
-	Downloads int    `json:"download_count"`
+	ID          int64      `json:"id"`
+	Name        string     `json:"name"`
+	Downloads   int        `json:"download_count"`
+	Size        int        `json:"size"`
+	ContentType string     `json:"content_type"`
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+	Uploader    string     `json:"uploader"`
+	URL         string     `json:"browser_download_url"`
 }
 
 type Release struct {
+	ID             int64          `json:"id"`
 	Name           string         `json:"name"`
+	Tag            string         `json:"tag_name"`
 	Date           time.Time      `json:"date"`
+	PublishedAt    *time.Time     `json:"published_at,omitempty"`
+	Prerelease     bool           `json:"prerelease"`
+	Draft          bool           `json:"draft"`
+	Author         string         `json:"author"`
+	URL            string         `json:"html_url"`
 	Assets         []ReleaseAsset `json:"assets"`
 	TotalDownloads int            `json:"total_downloads"`
 }
 
 type DownloadStatsService interface {
 	FetchReleaseHistory() (*ReleaseHistory, error)
-	FormatDownloadStats(*ReleaseHistory) (string, error)
+}
+
+// ContextDownloadStatsService is implemented by services whose fetches can be
+// cancelled or given a deadline through a context.
+type ContextDownloadStatsService interface {
+	DownloadStatsService
+	FetchReleaseHistoryContext(ctx context.Context) (*ReleaseHistory, error)
+}
+
+// fetchReleaseHistory fetches with ctx when dss supports it. Otherwise ctx is
+// only checked before the fetch starts.
+func fetchReleaseHistory(ctx context.Context, dss DownloadStatsService) (*ReleaseHistory, error) {
+	if cdss, ok := dss.(ContextDownloadStatsService); ok {
+		return cdss.FetchReleaseHistoryContext(ctx)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return dss.FetchReleaseHistory()
+}
+
+// historyArranger is implemented by services that sort and limit the
+// histories they fetch before they are formatted.
+type historyArranger interface {
+	Arrange(history *ReleaseHistory) (*ReleaseHistory, error)
 }
 
 type GitHubDownloadStatsOptions struct {
-	Release     string
-	JsonOut     bool
-	ApiEndpoint string
-	Token       string
-	PreRelease  bool
+	Release       string
+	Versions      string
+	Since         time.Time
+	Until         time.Time
+	IncludeAssets []string
+	ExcludeAssets []string
+	Format        string
+	ApiEndpoint   string
+	Token         string
+	PreRelease    bool
+	Drafts        bool
+	Sort          string
+	Descending    bool
+	Top           int
+	TopAssets     int
+	Concurrency   int
+	MaxRetries    int
+	MaxWait       time.Duration
+	Usage         *QuotaUsage
 }
 
 type GitHubDownloadStatsService struct {
@@ -49,30 +99,68 @@ type GitHubDownloadStatsService struct {
 	repo    string
 	client  *github.Client
 	options *GitHubDownloadStatsOptions
+
+	rate   github.Rate
+	rateMu sync.Mutex
 }
 
-func NewGitHubDownloadStatsService(owner string, repo string, options *GitHubDownloadStatsOptions) *GitHubDownloadStatsService {
-	client := github.NewClient(nil)
+// NewGitHubDownloadStatsService returns a service for the repository
+// owner/repo. The repository name and options are validated, and any problem
+// is reported as a *ValidationError.
+func NewGitHubDownloadStatsService(owner string, repo string, options *GitHubDownloadStatsOptions, opts ...ServiceOption) (*GitHubDownloadStatsService, error) {
+	if err := validateRepository(owner, repo); err != nil {
+		return nil, err
+	}
 
+	client, err := newGitHubClient(options, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GitHubDownloadStatsService{
+		owner:   owner,
+		repo:    repo,
+		client:  client,
+		options: options,
+	}, nil
+}
+
+func newGitHubClient(options *GitHubDownloadStatsOptions, opts ...ServiceOption) (*github.Client, error) {
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+
+	config := &serviceConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	httpClient := config.httpClient
 	if options.Token != "" {
+		// Copy the client rather than use oauth2.NewClient, which keeps only
+		// its transport and so drops e.g. its timeout.
+		c := &http.Client{}
+		if httpClient != nil {
+			*c = *httpClient
+		}
 		tokenSource := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: options.Token})
-		client = github.NewClient(oauth2.NewClient(context.Background(), tokenSource))
+		c.Transport = &oauth2.Transport{
+			Base:   c.Transport,
+			Source: oauth2.ReuseTokenSource(nil, tokenSource),
+		}
+		httpClient = c
 	}
+	client := github.NewClient(httpClient)
 
 	if options.ApiEndpoint != "" {
-		baseURL, err := url.Parse(options.ApiEndpoint)
+		baseURL, err := normalizeEndpoint(options.ApiEndpoint)
 		if err != nil {
-			panic("invalid base URL: " + err.Error())
+			return nil, err
 		}
 		client.BaseURL = baseURL
 	}
 
-	return &GitHubDownloadStatsService{
-		owner:   owner,
-		repo:    repo,
-		client:  client,
-		options: options,
-	}
+	return client, nil
 }
 
 func includeGitHubRelease(r *github.RepositoryRelease, options *GitHubDownloadStatsOptions) bool {
@@ -83,6 +171,16 @@ func includeGitHubRelease(r *github.RepositoryRelease, options *GitHubDownloadSt
 		if options.PreRelease == false && r.GetPrerelease() == true {
 			return false
 		}
+		// Drafts are only listed for tokens with push access.
+		if !options.Drafts && r.GetDraft() {
+			return false
+		}
+		if !options.Since.IsZero() && releaseTime(r).Before(options.Since) {
+			return false
+		}
+		if !options.Until.IsZero() && releaseTime(r).After(options.Until) {
+			return false
+		}
 		if len(r.Assets) > 0 {
 			return true
 		}
@@ -90,97 +188,242 @@ func includeGitHubRelease(r *github.RepositoryRelease, options *GitHubDownloadSt
 	return false
 }
 
+// includeGitHubReleaseVersion reports whether the release's tag, or its name
+// when the tag is not a version, is within the constraint.
+func includeGitHubReleaseVersion(r *github.RepositoryRelease, constraint *Constraint) bool {
+	if constraint == nil {
+		return true
+	}
+	if _, err := ParseVersion(r.GetTagName()); err != nil {
+		return constraint.AllowsTag(r.GetName())
+	}
+	return constraint.AllowsTag(r.GetTagName())
+}
+
+// releaseTime returns when a release was published, or when it was created
+// for drafts, which have not been published.
+func releaseTime(r *github.RepositoryRelease) time.Time {
+	if r.PublishedAt != nil {
+		return r.GetPublishedAt().Time
+	}
+	return r.GetCreatedAt().Time
+}
+
+// optionalTime returns the time of ts, or nil if GitHub did not return one,
+// so that it is omitted from JSON rather than encoded as the zero time.
+func optionalTime(ts *github.Timestamp) *time.Time {
+	if ts == nil || ts.IsZero() {
+		return nil
+	}
+	t := ts.Time
+	return &t
+}
+
 func (ghds *GitHubDownloadStatsService) FetchReleaseHistory() (*ReleaseHistory, error) {
-	ctx := context.TODO()
-	opt := &github.ListOptions{
-		PerPage: 200,
+	return ghds.FetchReleaseHistoryContext(context.Background())
+}
+
+// FetchReleaseHistoryContext is like FetchReleaseHistory, but stops fetching
+// and returns ctx's error once ctx is done, including while waiting to retry.
+func (ghds *GitHubDownloadStatsService) FetchReleaseHistoryContext(ctx context.Context) (*ReleaseHistory, error) {
+	var constraint *Constraint
+	if ghds.options.Versions != "" {
+		var err error
+		constraint, err = ParseConstraint(ghds.options.Versions)
+		if err != nil {
+			return nil, err
+		}
+		constraint.IncludePrerelease = ghds.options.PreRelease
 	}
-	releaseList := []Release{}
-	releaseCount := 0
 
-	for {
-		releases, resp, err := ghds.client.Repositories.ListReleases(ctx, ghds.owner, ghds.repo, opt)
+	var filter *AssetFilter
+	if len(ghds.options.IncludeAssets) > 0 || len(ghds.options.ExcludeAssets) > 0 {
+		var err error
+		filter, err = NewAssetFilter(ghds.options.IncludeAssets, ghds.options.ExcludeAssets)
 		if err != nil {
 			return nil, err
 		}
+	}
 
-		for _, r := range releases {
-			if includeGitHubRelease(r, ghds.options) == true {
-				downloadTotal := 0
-				assets := []ReleaseAsset{}
-				for _, a := range r.Assets {
-					asset := ReleaseAsset{
-						Name:      a.GetName(),
-						Downloads: a.GetDownloadCount(),
-					}
-					downloadTotal += asset.Downloads
-					assets = append(assets, asset)
-				}
+	releases, err := ghds.listReleases(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-				release := Release{
-					Name:           r.GetName(),
-					Date:           r.GetCreatedAt().Time,
-					Assets:         assets,
-					TotalDownloads: downloadTotal,
+	releaseList := []Release{}
+	releaseCount := 0
+
+	for _, r := range releases {
+		if includeGitHubRelease(r, ghds.options) == true && includeGitHubReleaseVersion(r, constraint) {
+			downloadTotal := 0
+			assets := []ReleaseAsset{}
+			for _, a := range r.Assets {
+				if !filter.Match(a.GetName()) {
+					continue
+				}
+				asset := ReleaseAsset{
+					ID:          a.GetID(),
+					Name:        a.GetName(),
+					Downloads:   a.GetDownloadCount(),
+					Size:        a.GetSize(),
+					ContentType: a.GetContentType(),
+					CreatedAt:   optionalTime(a.CreatedAt),
+					UpdatedAt:   optionalTime(a.UpdatedAt),
+					Uploader:    a.GetUploader().GetLogin(),
+					URL:         a.GetBrowserDownloadURL(),
 				}
-				releaseList = append(releaseList, release)
-				releaseCount++
+				downloadTotal += asset.Downloads
+				assets = append(assets, asset)
+			}
+			// Like releases without assets, releases whose assets were all
+			// filtered out are left out.
+			if len(assets) == 0 {
+				continue
 			}
-		}
 
-		if resp.NextPage == 0 {
-			break
+			release := Release{
+				ID:             r.GetID(),
+				Name:           r.GetName(),
+				Tag:            r.GetTagName(),
+				Date:           r.GetCreatedAt().Time,
+				PublishedAt:    optionalTime(r.PublishedAt),
+				Prerelease:     r.GetPrerelease(),
+				Draft:          r.GetDraft(),
+				Author:         r.GetAuthor().GetLogin(),
+				URL:            r.GetHTMLURL(),
+				Assets:         assets,
+				TotalDownloads: downloadTotal,
+			}
+			releaseList = append(releaseList, release)
+			releaseCount++
 		}
-		opt.Page = resp.NextPage
 	}
 
-	return &ReleaseHistory{
-		Repository:   fmt.Sprintf("%s/%s", ghds.owner, ghds.repo),
+	history := &ReleaseHistory{
+		Repository:   ghds.Repository(),
 		Releases:     releaseList,
 		ReleaseCount: releaseCount,
-	}, nil
+	}
+
+	return history, nil
 }
 
-func (ghds *GitHubDownloadStatsService) FormatDownloadStats(history *ReleaseHistory) (string, error) {
-	if ghds.options.JsonOut {
-		obj, err := json.Marshal(history)
+// listReleases fetches every page of releases. Once the first response
+// reveals the last page number, the remaining pages are fetched concurrently
+// when the Concurrency option allows it. With the Since option set, pages are
+// fetched one at a time instead, stopping at the first page that reaches back
+// past Since, as releases are listed newest first.
+func (ghds *GitHubDownloadStatsService) listReleases(ctx context.Context) ([]*github.RepositoryRelease, error) {
+	opt := &github.ListOptions{
+		PerPage: 200,
+	}
+
+	releases, resp, err := ghds.fetchReleasePage(ctx, opt)
+	if err != nil {
+		return nil, err
+	}
+
+	since := ghds.options.Since
+	if resp.NextPage != 0 && resp.LastPage > resp.NextPage && ghds.options.Concurrency > 1 && since.IsZero() {
+		firstPage := resp.NextPage
+		pages := make([][]*github.RepositoryRelease, resp.LastPage-firstPage+1)
+		err := forEach(len(pages), ghds.options.Concurrency, func(i int) error {
+			pageOpt := &github.ListOptions{PerPage: opt.PerPage, Page: firstPage + i}
+			page, _, err := ghds.fetchReleasePage(ctx, pageOpt)
+			pages[i] = page
+			return err
+		})
 		if err != nil {
-			return "", err
+			return nil, err
 		}
 
-		return string(obj), nil
-
-	} else {
-		buf := new(bytes.Buffer)
-		w := tabwriter.NewWriter(buf, 0, 4, 1, ' ', tabwriter.TabIndent)
-		fmt.Fprintf(w, "Repository: %s/%s\n\n", ghds.owner, ghds.repo)
-		if len(history.Releases) > 0 {
-			for _, rel := range history.Releases {
-				fmt.Fprintf(w, "Release: %v\tDate: %v\n", rel.Name, rel.Date)
-				fmt.Fprintln(w, " ")
-				fmt.Fprintf(w, " Asset:\tDownloads:\n")
+		for _, page := range pages {
+			releases = append(releases, page...)
+		}
+		return releases, nil
+	}
 
-				for _, asset := range rel.Assets {
-					fmt.Fprintf(w, " - %v\t\t%v\n", asset.Name, asset.Downloads)
-				}
+	page := releases
+	for resp.NextPage != 0 {
+		if !since.IsZero() && len(page) > 0 && releaseTime(page[len(page)-1]).Before(since) {
+			break
+		}
 
-				fmt.Fprintf(w, "\nTotal downloads:\t%v\n\n", rel.TotalDownloads)
-				fmt.Fprintf(w, "------------------------------------------\n")
-				w.Flush()
-			}
+		opt.Page = resp.NextPage
+		page, resp, err = ghds.fetchReleasePage(ctx, opt)
+		if err != nil {
+			return nil, err
 		}
+		releases = append(releases, page...)
+	}
+
+	return releases, nil
+}
+
+func (ghds *GitHubDownloadStatsService) fetchReleasePage(ctx context.Context, opt *github.ListOptions) ([]*github.RepositoryRelease, *github.Response, error) {
+	var releases []*github.RepositoryRelease
+	resp, err := retry(ctx, ghds.options, func() (*github.Response, error) {
+		var (
+			resp *github.Response
+			err  error
+		)
+		releases, resp, err = ghds.client.Repositories.ListReleases(ctx, ghds.owner, ghds.repo, opt)
+		ghds.updateRate(resp)
+		return resp, err
+	})
+	return releases, resp, err
+}
 
-		return buf.String(), nil
+// Repository returns the repository's full name, e.g. "owner/repo".
+func (ghds *GitHubDownloadStatsService) Repository() string {
+	return fmt.Sprintf("%s/%s", ghds.owner, ghds.repo)
+}
+
+// Rate returns the API rate limit reported by the most recent GitHub response.
+func (ghds *GitHubDownloadStatsService) Rate() github.Rate {
+	ghds.rateMu.Lock()
+	defer ghds.rateMu.Unlock()
+	return ghds.rate
+}
+
+func (ghds *GitHubDownloadStatsService) updateRate(resp *github.Response) {
+	if resp == nil || resp.Rate.Limit == 0 {
+		return
 	}
+	ghds.rateMu.Lock()
+	ghds.rate = resp.Rate
+	ghds.rateMu.Unlock()
+}
+
+// Arrange sorts and limits a history fetched by the service as its options
+// ask, see GitHubDownloadStatsOptions.Arrange.
+func (ghds *GitHubDownloadStatsService) Arrange(history *ReleaseHistory) (*ReleaseHistory, error) {
+	return ghds.options.Arrange(history)
+}
+
+// FormatDownloadStats renders a release history in the format selected by
+// the service's options. New code should use FormatHistory instead.
+func (ghds *GitHubDownloadStatsService) FormatDownloadStats(history *ReleaseHistory) (string, error) {
+	return FormatHistory(history, ghds.options.Format)
+}
+
+func Build(dss DownloadStatsService, format string) (string, error) {
+	return BuildContext(context.Background(), dss, format)
 }
 
-func Build(dss DownloadStatsService) (string, error) {
-	history, err := dss.FetchReleaseHistory()
+// BuildContext is like Build, but fetches the release history with ctx.
+func BuildContext(ctx context.Context, dss DownloadStatsService, format string) (string, error) {
+	history, err := fetchReleaseHistory(ctx, dss)
 	if err != nil {
 		return "", err
 	}
+	if a, ok := dss.(historyArranger); ok {
+		if history, err = a.Arrange(history); err != nil {
+			return "", err
+		}
+	}
 
-	out, err := dss.FormatDownloadStats(history)
+	out, err := FormatHistory(history, format)
 	if err != nil {
 		return "", err
 	}
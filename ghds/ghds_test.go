@@ -1,11 +1,16 @@
 package ghds
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"reflect"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -40,15 +45,30 @@ func TestFetchReleaseHistory(t *testing.T) {
 	mux.HandleFunc("/repos/foo/bar/releases", func(w http.ResponseWriter, r *http.Request) {
 		fmt.Fprint(w, `[
   {
+    "id": 1,
     "tag_name": "v1.0.0",
     "name": "v1.0.0",
     "prerelease": false,
+    "draft": false,
     "created_at": "2013-02-27T19:35:32Z",
+    "published_at": "2013-02-27T19:35:32Z",
+    "html_url": "https://github.com/foo/bar/releases/v1.0.0",
+    "author": {
+      "login": "octocat"
+    },
     "assets": [
       {
+        "id": 10,
         "name": "example.zip",
         "content_type": "application/zip",
-        "download_count": 42
+        "size": 1024,
+        "download_count": 42,
+        "created_at": "2013-02-27T19:35:32Z",
+        "updated_at": "2013-02-27T19:35:32Z",
+        "browser_download_url": "https://github.com/foo/bar/releases/download/v1.0.0/example.zip",
+        "uploader": {
+          "login": "octocat"
+        }
       },
       {
         "name": "example.tar.gz",
@@ -86,26 +106,41 @@ func TestFetchReleaseHistory(t *testing.T) {
 		Repository: "foo/bar",
 		Releases: []Release{
 			Release{
-				Name: "v1.0.0",
-				Date: timeOne,
+				ID:          1,
+				Name:        "v1.0.0",
+				Tag:         "v1.0.0",
+				Date:        timeOne,
+				PublishedAt: &timeOne,
+				Author:      "octocat",
+				URL:         "https://github.com/foo/bar/releases/v1.0.0",
 				Assets: []ReleaseAsset{
 					ReleaseAsset{
-						Name:      "example.zip",
-						Downloads: 42,
+						ID:          10,
+						Name:        "example.zip",
+						Downloads:   42,
+						Size:        1024,
+						ContentType: "application/zip",
+						CreatedAt:   &timeOne,
+						UpdatedAt:   &timeOne,
+						Uploader:    "octocat",
+						URL:         "https://github.com/foo/bar/releases/download/v1.0.0/example.zip",
 					}, ReleaseAsset{
-						Name:      "example.tar.gz",
-						Downloads: 42,
+						Name:        "example.tar.gz",
+						Downloads:   42,
+						ContentType: "application/zip",
 					},
 				},
 				TotalDownloads: 84,
 			},
 			Release{
 				Name: "v2.0.0",
+				Tag:  "v2.0.0",
 				Date: timeTwo,
 				Assets: []ReleaseAsset{
 					ReleaseAsset{
-						Name:      "example.zip",
-						Downloads: 85,
+						Name:        "example.zip",
+						Downloads:   85,
+						ContentType: "application/zip",
 					},
 				},
 				TotalDownloads: 85,
@@ -114,7 +149,7 @@ func TestFetchReleaseHistory(t *testing.T) {
 		ReleaseCount: 2,
 	}
 
-	dss := NewGitHubDownloadStatsService("foo", "bar", options)
+	dss := newTestService(t, "foo", "bar", options)
 	actual, err := dss.FetchReleaseHistory()
 	if err != nil {
 		t.Fatalf("unexpected error: %s", err)
@@ -123,6 +158,163 @@ func TestFetchReleaseHistory(t *testing.T) {
 	if !reflect.DeepEqual(actual, expected) {
 		t.Errorf("got %v, expected %v", actual, expected)
 	}
+
+	// Missing times are omitted, and snapshots round-trip.
+	b, err := json.Marshal(actual)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if strings.Contains(string(b), "0001-01-01") {
+		t.Errorf("expected missing times to be omitted, got %s", b)
+	}
+	var decoded *ReleaseHistory
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(decoded, expected) {
+		t.Errorf("got %v, expected %v", decoded, expected)
+	}
+}
+
+func TestFetchReleaseHistoryPaginated(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/repos/foo/bar/releases", func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page == 0 {
+			page = 1
+		}
+		if page < 4 {
+			w.Header().Set("Link", fmt.Sprintf(`<%s/repos/foo/bar/releases?page=%d>; rel="next", <%s/repos/foo/bar/releases?page=4>; rel="last"`,
+				server.URL, page+1, server.URL))
+		}
+		fmt.Fprintf(w, `[{"tag_name": "v%d.0.0", "assets": [{"name": "example.zip", "download_count": %d}]}]`, page, page)
+	})
+
+	for _, concurrency := range []int{0, 1, 3, 10} {
+		options.Concurrency = concurrency
+		dss := newTestService(t, "foo", "bar", options)
+		actual, err := dss.FetchReleaseHistory()
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		tags := []string{}
+		for _, rel := range actual.Releases {
+			tags = append(tags, rel.Tag)
+		}
+		expected := []string{"v1.0.0", "v2.0.0", "v3.0.0", "v4.0.0"}
+		if !reflect.DeepEqual(tags, expected) {
+			t.Errorf("concurrency %d: got %v, expected %v", concurrency, tags, expected)
+		}
+	}
+}
+
+func TestFetchReleaseHistorySince(t *testing.T) {
+	setup()
+	defer teardown()
+
+	// Page n holds one release published on the (10 - n)th of January.
+	requested := []int{}
+	mux.HandleFunc("/repos/foo/bar/releases", func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page == 0 {
+			page = 1
+		}
+		requested = append(requested, page)
+		if page < 9 {
+			w.Header().Set("Link", fmt.Sprintf(`<%s/repos/foo/bar/releases?page=%d>; rel="next", <%s/repos/foo/bar/releases?page=9>; rel="last"`,
+				server.URL, page+1, server.URL))
+		}
+		fmt.Fprintf(w, `[{"tag_name": "v%d.0.0", "published_at": "2024-01-%02dT12:00:00Z", "assets": [{"name": "example.zip", "download_count": 1}]}]`,
+			10-page, 10-page)
+	})
+
+	options.Concurrency = 4
+	options.Since = time.Date(2024, 1, 6, 0, 0, 0, 0, time.UTC)
+	options.Until = time.Date(2024, 1, 8, 23, 59, 59, 0, time.UTC)
+	dss := newTestService(t, "foo", "bar", options)
+	actual, err := dss.FetchReleaseHistory()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	tags := []string{}
+	for _, rel := range actual.Releases {
+		tags = append(tags, rel.Tag)
+	}
+	expected := []string{"v8.0.0", "v7.0.0", "v6.0.0"}
+	if !reflect.DeepEqual(tags, expected) {
+		t.Errorf("got %v, expected %v", tags, expected)
+	}
+
+	// Pagination stops after the first release published before Since.
+	if expectedPages := []int{1, 2, 3, 4, 5}; !reflect.DeepEqual(requested, expectedPages) {
+		t.Errorf("requested pages %v, expected %v", requested, expectedPages)
+	}
+}
+
+func TestFetchReleaseHistoryAssetFilter(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/repos/foo/bar/releases", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[
+			{"tag_name": "v2.0.0", "assets": [{"name": "checksums.txt", "download_count": 500}]},
+			{"tag_name": "v1.0.0", "assets": [
+				{"name": "example.zip", "download_count": 42},
+				{"name": "example.zip.sig", "download_count": 300}
+			]}
+		]`)
+	})
+
+	options.ExcludeAssets = ChecksumAssetPatterns
+	dss := newTestService(t, "foo", "bar", options)
+	actual, err := dss.FetchReleaseHistory()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if actual.ReleaseCount != 1 || actual.Releases[0].Tag != "v1.0.0" {
+		t.Fatalf("expected only v1.0.0, actual %+v", actual.Releases)
+	}
+	if rel := actual.Releases[0]; len(rel.Assets) != 1 || rel.TotalDownloads != 42 {
+		t.Errorf("expected 1 asset with 42 downloads, actual %d with %d", len(rel.Assets), rel.TotalDownloads)
+	}
+}
+
+func TestFetchReleaseHistoryContext(t *testing.T) {
+	setup()
+	defer teardown()
+
+	// The second page never responds, so the fetch only returns once the
+	// context's deadline passes.
+	mux.HandleFunc("/repos/foo/bar/releases", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "" {
+			w.Header().Set("Link", fmt.Sprintf(`<%s/repos/foo/bar/releases?page=2>; rel="next"`, server.URL))
+			fmt.Fprint(w, `[{"tag_name": "v2.0.0", "assets": [{"name": "example.zip", "download_count": 1}]}]`)
+			return
+		}
+		<-r.Context().Done()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	dss := newTestService(t, "foo", "bar", options)
+	start := time.Now()
+	_, err := dss.FetchReleaseHistoryContext(ctx)
+	if err != context.DeadlineExceeded {
+		t.Errorf("expected %v, got %v", context.DeadlineExceeded, err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected fetch to return promptly, took %v", elapsed)
+	}
+
+	if _, err := BuildContext(ctx, dss, FormatText); err != context.DeadlineExceeded {
+		t.Errorf("BuildContext: expected %v, got %v", context.DeadlineExceeded, err)
+	}
 }
 
 func TestFormatDownloadStats(t *testing.T) {
@@ -153,12 +345,18 @@ func TestFormatDownloadStats(t *testing.T) {
 				TotalDownloads: 84,
 			},
 			Release{
-				Name: "v2.0.0",
-				Date: timeTwo,
+				Name:        "v2.0.0",
+				Tag:         "v2.0.0",
+				Date:        timeTwo,
+				PublishedAt: &timeTwo,
+				Prerelease:  true,
+				Author:      "octocat",
+				URL:         "https://github.com/foo/bar/releases/v2.0.0",
 				Assets: []ReleaseAsset{
 					ReleaseAsset{
 						Name:      "example.zip",
 						Downloads: 85,
+						Size:      1572864,
 					},
 				},
 				TotalDownloads: 85,
@@ -167,7 +365,7 @@ func TestFormatDownloadStats(t *testing.T) {
 		ReleaseCount: 2,
 	}
 
-	dss := NewGitHubDownloadStatsService("foo", "bar", options)
+	dss := newTestService(t, "foo", "bar", options)
 	actual, err := dss.FormatDownloadStats(history)
 	if err != nil {
 		t.Fatalf("unexpected error: %s", err)
@@ -178,12 +376,78 @@ func TestFormatDownloadStats(t *testing.T) {
 	}
 }
 
+func TestFormatDownloadStatsCSV(t *testing.T) {
+	timeOne, err := time.Parse(time.RFC3339, "2013-02-27T19:35:32Z")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	history := &ReleaseHistory{
+		Repository: "foo/bar",
+		Releases: []Release{
+			Release{
+				Name: "v1.0.0, \"stable\"",
+				Tag:  "v1.0.0",
+				Date: timeOne,
+				Assets: []ReleaseAsset{
+					ReleaseAsset{
+						Name:      "example.zip",
+						Downloads: 42,
+					}, ReleaseAsset{
+						Name:      "example.tar.gz",
+						Downloads: 42,
+					},
+				},
+				TotalDownloads: 84,
+			},
+			Release{
+				Name:  "v1.1.0",
+				Tag:   "v1.1.0",
+				Date:  timeOne,
+				Draft: true,
+				Assets: []ReleaseAsset{
+					ReleaseAsset{
+						Name: "example.zip",
+					},
+				},
+			},
+		},
+		ReleaseCount: 2,
+	}
+
+	var formatTests = []struct {
+		format   string
+		expected string
+	}{
+		{FormatCSV, expectedReleaseHistoryCSV},
+		{FormatTSV, expectedReleaseHistoryTSV},
+	}
+
+	for _, tt := range formatTests {
+		dss := newTestService(t, "foo", "bar", &GitHubDownloadStatsOptions{Format: tt.format})
+		actual, err := dss.FormatDownloadStats(history)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if actual != tt.expected {
+			t.Errorf("%s: got %v, expected %v", tt.format, actual, tt.expected)
+		}
+	}
+
+	dss := newTestService(t, "foo", "bar", &GitHubDownloadStatsOptions{Format: "yaml"})
+	if _, err := dss.FormatDownloadStats(history); err == nil {
+		t.Errorf("expected error for unknown format")
+	}
+}
+
 func TestIncludeGitHubRelease(t *testing.T) {
 	var (
 		emptyName = ""
 		name      = "v1"
 		id        = int64(1)
 		isTrue    = true
+		published = github.Timestamp{Time: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
 	)
 
 	var includeReleaseTests = []struct {
@@ -226,6 +490,31 @@ func TestIncludeGitHubRelease(t *testing.T) {
 			Name:   &name,
 			Assets: []github.ReleaseAsset{{ID: &id}},
 		}, &GitHubDownloadStatsOptions{Release: "v2"}, false},
+		// Is a draft, doesn't match
+		{&github.RepositoryRelease{
+			Draft:  &isTrue,
+			Assets: []github.ReleaseAsset{{ID: &id}},
+		}, &GitHubDownloadStatsOptions{}, false},
+		// Is a draft with drafts flag
+		{&github.RepositoryRelease{
+			Draft:  &isTrue,
+			Assets: []github.ReleaseAsset{{ID: &id}},
+		}, &GitHubDownloadStatsOptions{Drafts: true}, true},
+		// Published before Since
+		{&github.RepositoryRelease{
+			PublishedAt: &published,
+			Assets:      []github.ReleaseAsset{{ID: &id}},
+		}, &GitHubDownloadStatsOptions{Since: published.Add(time.Hour)}, false},
+		// Published after Until
+		{&github.RepositoryRelease{
+			PublishedAt: &published,
+			Assets:      []github.ReleaseAsset{{ID: &id}},
+		}, &GitHubDownloadStatsOptions{Until: published.Add(-time.Hour)}, false},
+		// Draft created within the range
+		{&github.RepositoryRelease{
+			CreatedAt: &published,
+			Assets:    []github.ReleaseAsset{{ID: &id}},
+		}, &GitHubDownloadStatsOptions{Since: published.Add(-time.Hour), Until: published.Add(time.Hour)}, true},
 	}
 
 	for _, tt := range includeReleaseTests {
@@ -236,10 +525,149 @@ func TestIncludeGitHubRelease(t *testing.T) {
 	}
 }
 
+func TestIncludeGitHubReleaseVersion(t *testing.T) {
+	var (
+		tag       = "v1.4.2"
+		otherTag  = "nightly"
+		name      = "Release 1.4.2"
+		emptyName = ""
+	)
+	constraint, err := ParseConstraint("^1.4")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var includeVersionTests = []struct {
+		input      *github.RepositoryRelease
+		constraint *Constraint
+		expected   bool
+	}{
+		// No constraint
+		{&github.RepositoryRelease{TagName: &otherTag}, nil, true},
+		// Tag within the range
+		{&github.RepositoryRelease{TagName: &tag}, constraint, true},
+		// Tag is not a version, falls back to the name
+		{&github.RepositoryRelease{TagName: &otherTag, Name: &tag}, constraint, true},
+		// Neither tag nor name is a version
+		{&github.RepositoryRelease{TagName: &otherTag, Name: &name}, constraint, false},
+		{&github.RepositoryRelease{TagName: &otherTag, Name: &emptyName}, constraint, false},
+	}
+
+	for _, tt := range includeVersionTests {
+		actual := includeGitHubReleaseVersion(tt.input, tt.constraint)
+		if actual != tt.expected {
+			t.Errorf("includeGitHubReleaseVersion(%v, %v):\nexpected %v, actual %v", tt.input, tt.constraint, tt.expected, actual)
+		}
+	}
+}
+
+// newTestService returns a service for owner/repo, failing the test if the
+// options are invalid.
+func newTestService(t *testing.T, owner string, repo string, options *GitHubDownloadStatsOptions) *GitHubDownloadStatsService {
+	t.Helper()
+	dss, err := NewGitHubDownloadStatsService(owner, repo, options)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return dss
+}
+
 func TestNewGitHubDownloadStatsService(t *testing.T) {
 	t.Run("passing no options should not panic", func(t *testing.T) {
 		NewGitHubDownloadStatsService("digitalocean", "doctl", &GitHubDownloadStatsOptions{})
 	})
+
+	t.Run("trailing slash is added to the endpoint", func(t *testing.T) {
+		dss := newTestService(t, "foo", "bar", &GitHubDownloadStatsOptions{ApiEndpoint: "https://ghe.example.com/api/v3"})
+		if actual := dss.client.BaseURL.String(); actual != "https://ghe.example.com/api/v3/" {
+			t.Errorf("got %s, expected https://ghe.example.com/api/v3/", actual)
+		}
+	})
+
+	var invalidTests = []struct {
+		owner   string
+		repo    string
+		options *GitHubDownloadStatsOptions
+		field   string
+	}{
+		{"", "bar", &GitHubDownloadStatsOptions{}, "owner"},
+		{"-foo", "bar", &GitHubDownloadStatsOptions{}, "owner"},
+		{"foo/bar", "bar", &GitHubDownloadStatsOptions{}, "owner"},
+		{"foo", "", &GitHubDownloadStatsOptions{}, "repo"},
+		{"foo", "..", &GitHubDownloadStatsOptions{}, "repo"},
+		{"foo", "bar baz", &GitHubDownloadStatsOptions{}, "repo"},
+		{"foo", "bar", &GitHubDownloadStatsOptions{ApiEndpoint: "::"}, "ApiEndpoint"},
+		{"foo", "bar", &GitHubDownloadStatsOptions{ApiEndpoint: "ftp://ghe.example.com/"}, "ApiEndpoint"},
+		{"foo", "bar", &GitHubDownloadStatsOptions{ApiEndpoint: "ghe.example.com/api/v3/"}, "ApiEndpoint"},
+		{"foo", "bar", &GitHubDownloadStatsOptions{Release: "v1.0.0", Versions: "1.x"}, "Release"},
+		{"foo", "bar", &GitHubDownloadStatsOptions{Versions: ">=foo"}, "Versions"},
+		{"foo", "bar", &GitHubDownloadStatsOptions{Since: time.Now(), Until: time.Now().Add(-time.Hour)}, "Since"},
+		{"foo", "bar", &GitHubDownloadStatsOptions{ExcludeAssets: []string{"["}}, "ExcludeAssets"},
+		{"foo", "bar", &GitHubDownloadStatsOptions{Sort: "size"}, "Sort"},
+		{"foo", "bar", &GitHubDownloadStatsOptions{Top: -1}, "Top"},
+	}
+
+	for _, tt := range invalidTests {
+		_, err := NewGitHubDownloadStatsService(tt.owner, tt.repo, tt.options)
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Errorf("NewGitHubDownloadStatsService(%q, %q, %+v): expected a *ValidationError, got %v", tt.owner, tt.repo, tt.options, err)
+			continue
+		}
+		if validationErr.Field != tt.field {
+			t.Errorf("NewGitHubDownloadStatsService(%q, %q, %+v): expected an error for %s, got %v", tt.owner, tt.repo, tt.options, tt.field, err)
+		}
+	}
+}
+
+func TestWithHTTPClient(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/repos/foo/bar/releases", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Test") != "custom" {
+			t.Errorf("expected request from the custom client, got headers %v", r.Header)
+		}
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			t.Errorf("expected token to be sent, got %q", r.Header.Get("Authorization"))
+		}
+		fmt.Fprint(w, `[]`)
+	})
+
+	client := &http.Client{Transport: headerTransport{"X-Test", "custom"}}
+	options.Token = "secret"
+	dss, err := NewGitHubDownloadStatsService("foo", "bar", options, WithHTTPClient(client))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := dss.FetchReleaseHistory(); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	// The client's timeout is kept when a token is set.
+	mux.HandleFunc("/repos/foo/slow/releases", func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+	client = &http.Client{Transport: headerTransport{"X-Test", "custom"}, Timeout: 50 * time.Millisecond}
+	dss, err = NewGitHubDownloadStatsService("foo", "slow", options, WithHTTPClient(client))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := dss.FetchReleaseHistory(); err == nil {
+		t.Errorf("expected the client timeout to fail a slow request")
+	}
+}
+
+// headerTransport adds a header to every request.
+type headerTransport struct {
+	name  string
+	value string
+}
+
+func (h headerTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.Header.Set(h.name, h.value)
+	return http.DefaultTransport.RoundTrip(r)
 }
 
 const (
@@ -254,13 +682,26 @@ Release: v1.0.0 Date: 2013-02-27 19:35:32 +0000 UTC
 Total downloads: 84
 
 ------------------------------------------
-Release: v2.0.0 Date: 2013-03-27 19:35:32 +0000 UTC
+Release: v2.0.0 (pre-release) Date: 2013-03-27 19:35:32 +0000 UTC
+Tag: v2.0.0                   Published: 2013-03-27 19:35:32 +0000 UTC
+Author: octocat               URL: https://github.com/foo/bar/releases/v2.0.0
  
- Asset:        Downloads:
- - example.zip  85
+ Asset:        Downloads: Size:
+ - example.zip  85        1.5 MiB
 
 Total downloads: 85
 
 ------------------------------------------
 `
+
+	expectedReleaseHistoryCSV = `repository,release,tag,date,asset,downloads,release_total_downloads,prerelease,draft
+foo/bar,"v1.0.0, ""stable""",v1.0.0,2013-02-27T19:35:32Z,example.zip,42,84,false,false
+foo/bar,"v1.0.0, ""stable""",v1.0.0,2013-02-27T19:35:32Z,example.tar.gz,42,84,false,false
+foo/bar,v1.1.0,v1.1.0,2013-02-27T19:35:32Z,example.zip,0,0,false,true
+`
+
+	expectedReleaseHistoryTSV = "repository\trelease\ttag\tdate\tasset\tdownloads\trelease_total_downloads\tprerelease\tdraft\n" +
+		"foo/bar\t\"v1.0.0, \"\"stable\"\"\"\tv1.0.0\t2013-02-27T19:35:32Z\texample.zip\t42\t84\tfalse\tfalse\n" +
+		"foo/bar\t\"v1.0.0, \"\"stable\"\"\"\tv1.0.0\t2013-02-27T19:35:32Z\texample.tar.gz\t42\t84\tfalse\tfalse\n" +
+		"foo/bar\tv1.1.0\tv1.1.0\t2013-02-27T19:35:32Z\texample.zip\t0\t0\tfalse\ttrue\n"
 )
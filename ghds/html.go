@@ -0,0 +1,214 @@
+package ghds
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+)
+
+// htmlFormatter renders a self-contained HTML page with a bar chart of
+// downloads per release, a platform breakdown and a sortable table of assets.
+// Styles and scripts are inlined so the page can be published as is.
+type htmlFormatter struct {
+	classifier *AssetClassifier
+}
+
+// NewHTMLFormatter returns the HTML formatter, classifying assets for the
+// platform breakdown with classifier, or the default rules if it is nil.
+func NewHTMLFormatter(classifier *AssetClassifier) Formatter {
+	return htmlFormatter{classifier: classifier}
+}
+
+func (f htmlFormatter) Format(history *ReleaseHistory) (string, error) {
+	return f.render(history.Repository, []*ReleaseHistory{history})
+}
+
+func (f htmlFormatter) FormatReport(report *Report) (string, error) {
+	names := make([]string, 0, len(report.Repositories))
+	for _, history := range report.Repositories {
+		names = append(names, history.Repository)
+	}
+	return f.render(strings.Join(names, ", "), report.Repositories)
+}
+
+type htmlPage struct {
+	Title          string
+	Repositories   []htmlRepository
+	Breakdown      []htmlPlatformSection
+	Assets         []htmlAsset
+	MultipleRepos  bool
+	ReleaseCount   int
+	TotalDownloads int
+}
+
+type htmlRepository struct {
+	*ReleaseHistory
+	MaxDownloads int
+}
+
+type htmlPlatformSection struct {
+	Title          string
+	Counts         []PlatformCount
+	TotalDownloads int
+}
+
+type htmlAsset struct {
+	Repository string
+	Release    Release
+	Asset      ReleaseAsset
+	Platform   Platform
+}
+
+func (f htmlFormatter) render(title string, histories []*ReleaseHistory) (string, error) {
+	classifier := f.classifier
+	if classifier == nil {
+		var err error
+		if classifier, err = NewAssetClassifier(nil); err != nil {
+			return "", err
+		}
+	}
+
+	page := htmlPage{Title: title, MultipleRepos: len(histories) > 1}
+	for _, history := range histories {
+		repo := htmlRepository{ReleaseHistory: history}
+		for _, rel := range history.Releases {
+			if rel.TotalDownloads > repo.MaxDownloads {
+				repo.MaxDownloads = rel.TotalDownloads
+			}
+			for _, asset := range rel.Assets {
+				page.Assets = append(page.Assets, htmlAsset{
+					Repository: history.Repository,
+					Release:    rel,
+					Asset:      asset,
+					Platform:   classifier.Classify(asset.Name),
+				})
+			}
+		}
+		page.Repositories = append(page.Repositories, repo)
+		page.ReleaseCount += len(history.Releases)
+		page.TotalDownloads += history.TotalDownloads()
+	}
+
+	breakdown := classifier.Breakdown(histories...)
+	page.Breakdown = []htmlPlatformSection{
+		{"Operating system", breakdown.OS, breakdown.TotalDownloads},
+		{"Architecture", breakdown.Arch, breakdown.TotalDownloads},
+		{"Package", breakdown.Package, breakdown.TotalDownloads},
+	}
+
+	buf := new(bytes.Buffer)
+	if err := htmlTemplate.Execute(buf, page); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// barWidth sizes a bar as a share of the largest bar in its chart.
+func barWidth(part int, max int) template.CSS {
+	if max <= 0 {
+		return "width: 0%"
+	}
+	return template.CSS(fmt.Sprintf("width: %.1f%%", float64(part)*100/float64(max)))
+}
+
+var htmlTemplate = template.Must(template.New("html").Funcs(template.FuncMap{
+	"count":   formatCount,
+	"size":    formatSize,
+	"share":   formatShare,
+	"bar":     barWidth,
+	"marker":  releaseMarker,
+	"release": releaseKey,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Download statistics: {{.Title}}</title>
+<style>
+body { font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em auto; max-width: 72em; padding: 0 1em; color: #24292f; }
+h1 { font-size: 1.6em; }
+h2 { font-size: 1.3em; border-bottom: 1px solid #d0d7de; padding-bottom: .3em; margin-top: 2em; }
+h3 { font-size: 1em; }
+.summary { display: flex; gap: 2em; }
+.summary div { font-size: .9em; color: #57606a; }
+.summary strong { display: block; font-size: 1.8em; color: #24292f; }
+.chart { display: grid; grid-template-columns: minmax(8em, max-content) 1fr max-content; gap: .3em .8em; align-items: center; font-size: .9em; }
+.track { background: #f6f8fa; border-radius: 3px; }
+.bar { background: #2da44e; height: 1.1em; border-radius: 3px; min-width: 1px; }
+.platforms { display: grid; grid-template-columns: repeat(auto-fit, minmax(20em, 1fr)); gap: 0 2em; }
+.platforms .bar { background: #0969da; }
+.muted { color: #57606a; }
+.num { text-align: right; font-variant-numeric: tabular-nums; }
+table { border-collapse: collapse; width: 100%; font-size: .9em; }
+th, td { padding: .35em .6em; border-bottom: 1px solid #d0d7de; text-align: left; }
+th { cursor: pointer; user-select: none; background: #f6f8fa; white-space: nowrap; }
+th[aria-sort="ascending"]::after { content: " \25B2"; }
+th[aria-sort="descending"]::after { content: " \25BC"; }
+</style>
+</head>
+<body>
+<h1>Download statistics: {{.Title}}</h1>
+<div class="summary">
+<div><strong>{{count .TotalDownloads}}</strong>downloads</div>
+<div><strong>{{count .ReleaseCount}}</strong>releases</div>
+<div><strong>{{count (len .Assets)}}</strong>assets</div>
+</div>
+{{range .Repositories}}
+<h2>Downloads per release{{if $.MultipleRepos}}: {{.Repository}}{{end}}</h2>
+{{if .Releases}}<div class="chart">
+{{$max := .MaxDownloads}}{{range .Releases}}<div title="{{.Name}}">{{release .}}<span class="muted">{{marker .}}</span></div><div class="track"><div class="bar" style="{{bar .TotalDownloads $max}}"></div></div><div class="num">{{count .TotalDownloads}}</div>
+{{end}}</div>
+{{else}}<p class="muted">No releases found.</p>
+{{end}}{{end}}
+<h2>Platforms</h2>
+<div class="platforms">
+{{range .Breakdown}}<div>
+<h3>{{.Title}}</h3>
+<div class="chart">
+{{$total := .TotalDownloads}}{{range .Counts}}<div>{{.Name}}</div><div class="track"><div class="bar" style="{{bar .Downloads $total}}"></div></div><div class="num">{{share .Downloads $total}}</div>
+{{end}}</div>
+</div>
+{{end}}</div>
+<h2>Assets</h2>
+<table id="assets">
+<thead>
+<tr>{{if .MultipleRepos}}<th>Repository</th>{{end}}<th>Release</th><th data-type="number">Date</th><th>Asset</th><th>OS</th><th>Arch</th><th>Package</th><th data-type="number" class="num">Size</th><th data-type="number" class="num">Downloads</th></tr>
+</thead>
+<tbody>
+{{range .Assets}}<tr>{{if $.MultipleRepos}}<td>{{.Repository}}</td>{{end}}<td>{{release .Release}}{{marker .Release}}</td><td data-value="{{.Release.Date.Unix}}">{{.Release.Date.Format "2006-01-02"}}</td><td>{{.Asset.Name}}</td><td>{{.Platform.OS}}</td><td>{{.Platform.Arch}}</td><td>{{.Platform.Package}}</td><td class="num" data-value="{{.Asset.Size}}">{{if .Asset.Size}}{{size .Asset.Size}}{{end}}</td><td class="num" data-value="{{.Asset.Downloads}}">{{count .Asset.Downloads}}</td></tr>
+{{end}}</tbody>
+</table>
+<script>
+(function () {
+  var table = document.getElementById("assets");
+  var headers = table.tHead.rows[0].cells;
+  function value(row, i, numeric) {
+    var cell = row.cells[i];
+    var v = cell.hasAttribute("data-value") ? cell.getAttribute("data-value") : cell.textContent;
+    return numeric ? parseFloat(v) || 0 : v.toLowerCase();
+  }
+  function sort(i) {
+    var th = headers[i];
+    var numeric = th.getAttribute("data-type") === "number";
+    var desc = th.getAttribute("aria-sort") !== "descending" && (numeric || th.hasAttribute("aria-sort"));
+    var body = table.tBodies[0];
+    var rows = Array.prototype.slice.call(body.rows).map(function (row, n) { return [row, n]; });
+    rows.sort(function (a, b) {
+      var x = value(a[0], i, numeric), y = value(b[0], i, numeric);
+      var c = x < y ? -1 : x > y ? 1 : 0;
+      return (desc ? -c : c) || a[1] - b[1];
+    });
+    rows.forEach(function (r) { body.appendChild(r[0]); });
+    for (var j = 0; j < headers.length; j++) headers[j].removeAttribute("aria-sort");
+    th.setAttribute("aria-sort", desc ? "descending" : "ascending");
+  }
+  for (var i = 0; i < headers.length; i++) {
+    headers[i].addEventListener("click", sort.bind(null, i));
+  }
+  sort(headers.length - 1);
+})();
+</script>
+</body>
+</html>
+`))
@@ -0,0 +1,99 @@
+package ghds
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatHTML(t *testing.T) {
+	history := &ReleaseHistory{
+		Repository: "foo/bar",
+		Releases: []Release{
+			{
+				Name:       "v2.0.0",
+				Tag:        "v2.0.0",
+				Date:       time.Date(2024, 3, 27, 19, 35, 32, 0, time.UTC),
+				Prerelease: true,
+				Assets: []ReleaseAsset{
+					{Name: "tool_2.0.0_linux_amd64.tar.gz", Downloads: 1200, Size: 1572864},
+					{Name: "<script>alert(1)</script>", Downloads: 3},
+				},
+				TotalDownloads: 1203,
+			},
+			{
+				Name: "v1.0.0",
+				Tag:  "v1.0.0",
+				Date: time.Date(2024, 2, 27, 19, 35, 32, 0, time.UTC),
+				Assets: []ReleaseAsset{
+					{Name: "tool_1.0.0_windows_386.zip", Downloads: 401},
+				},
+				TotalDownloads: 401,
+			},
+		},
+		ReleaseCount: 2,
+	}
+
+	actual, err := FormatHistory(history, FormatHTML)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for _, expected := range []string{
+		"<title>Download statistics: foo/bar</title>",
+		"<strong>1,604</strong>downloads",
+		`v2.0.0<span class="muted"> (pre-release)</span>`,
+		`style="width: 100.0%"`,
+		`style="width: 33.3%"`,
+		"<div>linux</div>",
+		"<div>windows</div>",
+		"<td>tool_2.0.0_linux_amd64.tar.gz</td><td>linux</td><td>amd64</td><td>tar.gz</td>",
+		`<td class="num" data-value="1572864">1.5 MiB</td>`,
+		"&lt;script&gt;alert(1)&lt;/script&gt;",
+	} {
+		if !strings.Contains(actual, expected) {
+			t.Errorf("expected output to contain %q", expected)
+		}
+	}
+	for _, unexpected := range []string{"<th>Repository</th>", "<script>alert", "http://", "https://"} {
+		if strings.Contains(actual, unexpected) {
+			t.Errorf("expected output not to contain %q", unexpected)
+		}
+	}
+
+	report, err := FormatReport(NewReport([]*ReleaseHistory{history, {Repository: "foo/baz"}}), FormatHTML)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for _, expected := range []string{
+		"<title>Download statistics: foo/bar, foo/baz</title>",
+		"<h2>Downloads per release: foo/baz</h2>",
+		"No releases found.",
+		"<th>Repository</th>",
+		"<td>foo/bar</td>",
+	} {
+		if !strings.Contains(report, expected) {
+			t.Errorf("expected report to contain %q", expected)
+		}
+	}
+}
+
+func TestHTMLFormatterClassifier(t *testing.T) {
+	classifier, err := NewAssetClassifier([]ClassifierRule{{Field: PlatformOS, Value: "plan9", Pattern: `plan9`}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	history := &ReleaseHistory{
+		Repository: "foo/bar",
+		Releases: []Release{
+			{Name: "v1.0.0", Assets: []ReleaseAsset{{Name: "tool-plan9.tar.gz", Downloads: 1}}, TotalDownloads: 1},
+		},
+	}
+	actual, err := NewHTMLFormatter(classifier).Format(history)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !strings.Contains(actual, "<td>plan9</td>") {
+		t.Errorf("expected assets to be classified with the given rules")
+	}
+}
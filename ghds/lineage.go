@@ -0,0 +1,153 @@
+package ghds
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// versionPlaceholder replaces the version in normalized asset names.
+const versionPlaceholder = "{version}"
+
+// AssetLineage follows each logical artifact of a repository, such as the
+// linux/amd64 tarball, across releases.
+type AssetLineage struct {
+	Repository string            `json:"repository"`
+	Artifacts  []ArtifactLineage `json:"artifacts"`
+}
+
+// ArtifactLineage is the download history of one logical artifact, with one
+// entry per release that shipped it, oldest first.
+type ArtifactLineage struct {
+	Artifact       string            `json:"artifact"`
+	Releases       []ArtifactRelease `json:"releases"`
+	TotalDownloads int               `json:"total_downloads"`
+}
+
+type ArtifactRelease struct {
+	Release   string    `json:"release"`
+	Tag       string    `json:"tag_name"`
+	Date      time.Time `json:"date"`
+	Asset     string    `json:"asset"`
+	Downloads int       `json:"download_count"`
+}
+
+// NormalizeAssetName replaces the version embedded in an asset name with a
+// placeholder, so that e.g. "tool_1.2.3_linux_amd64.tar.gz" from tag "v1.2.3"
+// becomes "tool_{version}_linux_amd64.tar.gz". The version must appear as a
+// whole token, so "v1.0.1" does not match inside "1.0.10" or "v6" inside
+// "armv6".
+func NormalizeAssetName(name string, tag string) string {
+	for _, version := range versionCandidates(tag) {
+		for offset := 0; offset < len(name); {
+			i := strings.Index(name[offset:], version)
+			if i < 0 {
+				break
+			}
+			start, end := offset+i, offset+i+len(version)
+			if tokenBoundary(name, start-1, -1) && tokenBoundary(name, end, 1) {
+				return name[:start] + versionPlaceholder + name[end:]
+			}
+			offset = start + 1
+		}
+	}
+	return name
+}
+
+// tokenBoundary reports whether name[i] ends a token that is next to it in
+// direction dir. The edges of name and characters other than letters and
+// digits are boundaries, except for a dot followed by a digit, which
+// continues a version number.
+func tokenBoundary(name string, i int, dir int) bool {
+	if i < 0 || i >= len(name) {
+		return true
+	}
+	if isAlphanumeric(name[i]) {
+		return false
+	}
+	if name[i] == '.' {
+		next := i + dir
+		return next < 0 || next >= len(name) || name[next] < '0' || name[next] > '9'
+	}
+	return true
+}
+
+func isAlphanumeric(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// versionCandidates returns the forms a tag may take inside an asset name,
+// longest first: the tag itself, without a path prefix such as "tool/", and
+// without a leading "v".
+func versionCandidates(tag string) []string {
+	candidates := []string{}
+	add := func(s string) {
+		if s == "" {
+			return
+		}
+		for _, c := range candidates {
+			if c == s {
+				return
+			}
+		}
+		candidates = append(candidates, s)
+	}
+
+	add(tag)
+	if i := strings.LastIndex(tag, "/"); i >= 0 {
+		tag = tag[i+1:]
+		add(tag)
+	}
+	if len(tag) > 1 && (tag[0] == 'v' || tag[0] == 'V') && tag[1] >= '0' && tag[1] <= '9' {
+		add(tag[1:])
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return len(candidates[i]) > len(candidates[j])
+	})
+	return candidates
+}
+
+// Lineage groups the assets of every release by their normalized name.
+// Artifacts are ordered by total downloads, most first.
+func Lineage(history *ReleaseHistory) *AssetLineage {
+	releases := append([]Release{}, history.Releases...)
+	sort.SliceStable(releases, func(i, j int) bool {
+		return releases[i].Date.Before(releases[j].Date)
+	})
+
+	artifacts := map[string]*ArtifactLineage{}
+	order := []string{}
+	for _, rel := range releases {
+		for _, asset := range rel.Assets {
+			name := NormalizeAssetName(asset.Name, releaseKey(rel))
+			al, ok := artifacts[name]
+			if !ok {
+				al = &ArtifactLineage{Artifact: name, Releases: []ArtifactRelease{}}
+				artifacts[name] = al
+				order = append(order, name)
+			}
+			al.Releases = append(al.Releases, ArtifactRelease{
+				Release:   rel.Name,
+				Tag:       rel.Tag,
+				Date:      rel.Date,
+				Asset:     asset.Name,
+				Downloads: asset.Downloads,
+			})
+			al.TotalDownloads += asset.Downloads
+		}
+	}
+
+	lineage := &AssetLineage{
+		Repository: history.Repository,
+		Artifacts:  make([]ArtifactLineage, 0, len(order)),
+	}
+	for _, name := range order {
+		lineage.Artifacts = append(lineage.Artifacts, *artifacts[name])
+	}
+	sort.SliceStable(lineage.Artifacts, func(i, j int) bool {
+		return lineage.Artifacts[i].TotalDownloads > lineage.Artifacts[j].TotalDownloads
+	})
+
+	return lineage
+}
@@ -0,0 +1,140 @@
+package ghds
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNormalizeAssetName(t *testing.T) {
+	var normalizeTests = []struct {
+		name     string
+		tag      string
+		expected string
+	}{
+		{"tool_1.2.3_linux_amd64.tar.gz", "v1.2.3", "tool_{version}_linux_amd64.tar.gz"},
+		{"tool-v1.2.3-x86_64-apple-darwin.zip", "v1.2.3", "tool-{version}-x86_64-apple-darwin.zip"},
+		{"tool_1.2.3_windows_amd64.zip", "tool/v1.2.3", "tool_{version}_windows_amd64.zip"},
+		{"tool-2024.01.02.tar.gz", "2024.01.02", "tool-{version}.tar.gz"},
+		{"checksums.txt", "v1.2.3", "checksums.txt"},
+		{"tool_linux_amd64", "", "tool_linux_amd64"},
+		{"tool_6_linux_armv6.tar.gz", "v6", "tool_{version}_linux_armv6.tar.gz"},
+		{"tool_1.0.10_linux_amd64.tar.gz", "v1.0.1", "tool_1.0.10_linux_amd64.tar.gz"},
+		{"tool_1.0.1.tar.gz", "v1.0.1", "tool_{version}.tar.gz"},
+		{"tool_1.0.1.5_linux_amd64.tar.gz", "v1.0.1", "tool_1.0.1.5_linux_amd64.tar.gz"},
+		{"tool_v1.0.1_1.0.1.zip", "v1.0.1", "tool_{version}_1.0.1.zip"},
+	}
+
+	for _, tt := range normalizeTests {
+		actual := NormalizeAssetName(tt.name, tt.tag)
+		if actual != tt.expected {
+			t.Errorf("NormalizeAssetName(%s, %s): expected %s, actual %s", tt.name, tt.tag, tt.expected, actual)
+		}
+	}
+}
+
+func TestLineage(t *testing.T) {
+	history := &ReleaseHistory{
+		Repository: "o/r",
+		Releases: []Release{
+			{Name: "v1.1.0", Tag: "v1.1.0", Date: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), Assets: []ReleaseAsset{
+				{Name: "tool_1.1.0_linux_amd64.tar.gz", Downloads: 300},
+				{Name: "tool_1.1.0_darwin_arm64.zip", Downloads: 10},
+			}},
+			{Name: "v1.0.0", Tag: "v1.0.0", Date: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Assets: []ReleaseAsset{
+				{Name: "tool_1.0.0_linux_amd64.tar.gz", Downloads: 100},
+				{Name: "checksums.txt", Downloads: 5},
+			}},
+		},
+		ReleaseCount: 2,
+	}
+
+	lineage := Lineage(history)
+	if lineage.Repository != "o/r" {
+		t.Errorf("Repository: expected o/r, actual %s", lineage.Repository)
+	}
+	if len(lineage.Artifacts) != 3 {
+		t.Fatalf("expected 3 artifacts, actual %d: %+v", len(lineage.Artifacts), lineage.Artifacts)
+	}
+
+	linux := lineage.Artifacts[0]
+	if linux.Artifact != "tool_{version}_linux_amd64.tar.gz" || linux.TotalDownloads != 400 {
+		t.Errorf("first artifact: expected tool_{version}_linux_amd64.tar.gz with 400 downloads, actual %s with %d",
+			linux.Artifact, linux.TotalDownloads)
+	}
+	if len(linux.Releases) != 2 || linux.Releases[0].Tag != "v1.0.0" || linux.Releases[1].Tag != "v1.1.0" {
+		t.Errorf("expected releases oldest first, actual %+v", linux.Releases)
+	}
+	if linux.Releases[0].Asset != "tool_1.0.0_linux_amd64.tar.gz" {
+		t.Errorf("expected original asset name, actual %s", linux.Releases[0].Asset)
+	}
+
+	if lineage.Artifacts[1].Artifact != "tool_{version}_darwin_arm64.zip" || lineage.Artifacts[2].Artifact != "checksums.txt" {
+		t.Errorf("expected artifacts ordered by downloads, actual %s, %s",
+			lineage.Artifacts[1].Artifact, lineage.Artifacts[2].Artifact)
+	}
+}
+
+func TestFormatLineage(t *testing.T) {
+	history := &ReleaseHistory{
+		Repository: "o/r",
+		Releases: []Release{
+			{Name: "v1.1.0", Tag: "v1.1.0", Date: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), Assets: []ReleaseAsset{
+				{Name: "tool_1.1.0_linux_amd64.tar.gz", Downloads: 1300},
+				{Name: "checksums.txt", Downloads: 2},
+			}},
+			{Name: "First release", Tag: "v1.0.0", Date: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Assets: []ReleaseAsset{
+				{Name: "tool_1.0.0_linux_amd64.tar.gz", Downloads: 100},
+				{Name: "checksums.txt", Downloads: 5},
+			}},
+		},
+		ReleaseCount: 2,
+	}
+	lineages := []*AssetLineage{Lineage(history)}
+
+	var formatLineageTests = []struct {
+		format   string
+		expected string
+	}{
+		{FormatText, expectedLineageText},
+		{FormatCSV, expectedLineageCSV},
+	}
+
+	for _, tt := range formatLineageTests {
+		actual, err := FormatLineage(lineages, tt.format)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", tt.format, err)
+		}
+		if actual != tt.expected {
+			t.Errorf("%s: got:\n%s\nexpected:\n%s", tt.format, actual, tt.expected)
+		}
+	}
+}
+
+const expectedLineageText = `Repository: o/r
+
+Artifact: tool_{version}_linux_amd64.tar.gz
+ 
+ Release: Date:      Downloads: Change:
+ - v1.0.0 2024-01-01 100        
+ - v1.1.0 2024-02-01 1,300      +1,200
+
+Total downloads: 1,400
+
+------------------------------------------
+Artifact: checksums.txt
+ 
+ Release: Date:      Downloads: Change:
+ - v1.0.0 2024-01-01 5          
+ - v1.1.0 2024-02-01 2          -3
+
+Total downloads: 7
+
+------------------------------------------
+`
+
+const expectedLineageCSV = `repository,artifact,release,tag,date,asset,downloads
+o/r,tool_{version}_linux_amd64.tar.gz,First release,v1.0.0,2024-01-01T00:00:00Z,tool_1.0.0_linux_amd64.tar.gz,100
+o/r,tool_{version}_linux_amd64.tar.gz,v1.1.0,v1.1.0,2024-02-01T00:00:00Z,tool_1.1.0_linux_amd64.tar.gz,1300
+o/r,checksums.txt,First release,v1.0.0,2024-01-01T00:00:00Z,checksums.txt,5
+o/r,checksums.txt,v1.1.0,v1.1.0,2024-02-01T00:00:00Z,checksums.txt,2
+`
@@ -0,0 +1,101 @@
+package ghds
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"strings"
+)
+
+// markdownFormatter renders GitHub-flavored Markdown: a summary table of
+// releases followed by a collapsible list of assets for each release. The
+// output only depends on the history, so it can be committed and diffed.
+type markdownFormatter struct{}
+
+func (markdownFormatter) Format(history *ReleaseHistory) (string, error) {
+	buf := new(bytes.Buffer)
+	writeHistoryMarkdown(buf, history)
+	fmt.Fprintf(buf, "**Total downloads: %s**\n", formatCount(history.TotalDownloads()))
+	return buf.String(), nil
+}
+
+func (markdownFormatter) FormatReport(report *Report) (string, error) {
+	buf := new(bytes.Buffer)
+	for _, history := range report.Repositories {
+		writeHistoryMarkdown(buf, history)
+	}
+
+	fmt.Fprintf(buf, "## Summary\n\n")
+	fmt.Fprintf(buf, "| Repository | Releases | Downloads |\n| --- | ---: | ---: |\n")
+	for _, history := range report.Repositories {
+		fmt.Fprintf(buf, "| %s | %d | %s |\n", escapeMarkdown(history.Repository), history.ReleaseCount,
+			formatCount(history.TotalDownloads()))
+	}
+	fmt.Fprintf(buf, "| **Total** | | **%s** |\n\n", formatCount(report.TotalDownloads))
+	fmt.Fprintf(buf, "**Repositories: %d**\n\n", report.RepositoryCount)
+	fmt.Fprintf(buf, "**Grand total downloads: %s**\n", formatCount(report.TotalDownloads))
+	return buf.String(), nil
+}
+
+func writeHistoryMarkdown(buf *bytes.Buffer, history *ReleaseHistory) {
+	fmt.Fprintf(buf, "## %s\n\n", escapeMarkdown(history.Repository))
+	if len(history.Releases) == 0 {
+		fmt.Fprintf(buf, "No releases found.\n\n")
+		return
+	}
+
+	assetCount := 0
+	fmt.Fprintf(buf, "| Release | Tag | Date | Assets | Downloads |\n| --- | --- | --- | ---: | ---: |\n")
+	for _, rel := range history.Releases {
+		tag := ""
+		if rel.Tag != "" {
+			tag = "`" + strings.NewReplacer("`", "", "|", `\|`).Replace(rel.Tag) + "`"
+		}
+		fmt.Fprintf(buf, "| %s%s | %s | %s | %d | %s |\n", escapeMarkdown(rel.Name), releaseMarker(rel), tag,
+			rel.Date.Format("2006-01-02"), len(rel.Assets), formatCount(rel.TotalDownloads))
+		assetCount += len(rel.Assets)
+	}
+	fmt.Fprintf(buf, "| **Total** | | | %d | **%s** |\n\n", assetCount, formatCount(history.TotalDownloads()))
+
+	for _, rel := range history.Releases {
+		fmt.Fprintf(buf, "<details>\n<summary>%s%s: %s downloads</summary>\n\n", html.EscapeString(rel.Name),
+			releaseMarker(rel), formatCount(rel.TotalDownloads))
+
+		showSize := false
+		for _, asset := range rel.Assets {
+			if asset.Size > 0 {
+				showSize = true
+			}
+		}
+		if showSize {
+			fmt.Fprintf(buf, "| Asset | Downloads | Size |\n| --- | ---: | ---: |\n")
+		} else {
+			fmt.Fprintf(buf, "| Asset | Downloads |\n| --- | ---: |\n")
+		}
+		for _, asset := range rel.Assets {
+			if showSize {
+				fmt.Fprintf(buf, "| %s | %s | %s |\n", escapeMarkdown(asset.Name), formatCount(asset.Downloads), formatSize(asset.Size))
+			} else {
+				fmt.Fprintf(buf, "| %s | %s |\n", escapeMarkdown(asset.Name), formatCount(asset.Downloads))
+			}
+		}
+		fmt.Fprintf(buf, "\n</details>\n\n")
+	}
+}
+
+// markdownEscaper escapes characters that would otherwise end a table cell or
+// be read as emphasis, links or HTML.
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"|", `\|`,
+	"*", `\*`,
+	"`", "\\`",
+	"[", `\[`,
+	"]", `\]`,
+	"<", "&lt;",
+	">", "&gt;",
+)
+
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
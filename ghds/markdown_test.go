@@ -0,0 +1,103 @@
+package ghds
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatMarkdown(t *testing.T) {
+	history := &ReleaseHistory{
+		Repository: "foo/bar",
+		Releases: []Release{
+			{
+				Name:       "v2.0.0 | <beta>",
+				Tag:        "v2.0.0",
+				Date:       time.Date(2024, 3, 27, 19, 35, 32, 0, time.UTC),
+				Prerelease: true,
+				Assets: []ReleaseAsset{
+					{Name: "example_2.0.0.zip", Downloads: 1200, Size: 1572864},
+				},
+				TotalDownloads: 1200,
+			},
+			{
+				Name: "v1.0.0",
+				Tag:  "v1.0.0",
+				Date: time.Date(2024, 2, 27, 19, 35, 32, 0, time.UTC),
+				Assets: []ReleaseAsset{
+					{Name: "example.zip", Downloads: 42},
+					{Name: "checksums*.txt", Downloads: 7},
+				},
+				TotalDownloads: 49,
+			},
+		},
+		ReleaseCount: 2,
+	}
+
+	actual, err := FormatHistory(history, FormatMarkdown)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if actual != expectedMarkdown {
+		t.Errorf("got:\n%s\nexpected:\n%s", actual, expectedMarkdown)
+	}
+
+	again, _ := FormatHistory(history, FormatMarkdown)
+	if again != actual {
+		t.Errorf("expected deterministic output")
+	}
+
+	report, err := FormatReport(NewReport([]*ReleaseHistory{history, {Repository: "foo/baz", Releases: []Release{}}}), FormatMarkdown)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if expected := expectedMarkdownSummary; len(report) < len(expected) || report[len(report)-len(expected):] != expected {
+		t.Errorf("got:\n%s\nexpected to end with:\n%s", report, expected)
+	}
+}
+
+const (
+	expectedMarkdown = "## foo/bar\n" +
+		"\n" +
+		"| Release | Tag | Date | Assets | Downloads |\n" +
+		"| --- | --- | --- | ---: | ---: |\n" +
+		"| v2.0.0 \\| &lt;beta&gt; (pre-release) | `v2.0.0` | 2024-03-27 | 1 | 1,200 |\n" +
+		"| v1.0.0 | `v1.0.0` | 2024-02-27 | 2 | 49 |\n" +
+		"| **Total** | | | 3 | **1,249** |\n" +
+		"\n" +
+		"<details>\n" +
+		"<summary>v2.0.0 | &lt;beta&gt; (pre-release): 1,200 downloads</summary>\n" +
+		"\n" +
+		"| Asset | Downloads | Size |\n" +
+		"| --- | ---: | ---: |\n" +
+		"| example_2.0.0.zip | 1,200 | 1.5 MiB |\n" +
+		"\n" +
+		"</details>\n" +
+		"\n" +
+		"<details>\n" +
+		"<summary>v1.0.0: 49 downloads</summary>\n" +
+		"\n" +
+		"| Asset | Downloads |\n" +
+		"| --- | ---: |\n" +
+		"| example.zip | 42 |\n" +
+		"| checksums\\*.txt | 7 |\n" +
+		"\n" +
+		"</details>\n" +
+		"\n" +
+		"**Total downloads: 1,249**\n"
+
+	expectedMarkdownSummary = "## foo/baz\n" +
+		"\n" +
+		"No releases found.\n" +
+		"\n" +
+		"## Summary\n" +
+		"\n" +
+		"| Repository | Releases | Downloads |\n" +
+		"| --- | ---: | ---: |\n" +
+		"| foo/bar | 2 | 1,249 |\n" +
+		"| foo/baz | 0 | 0 |\n" +
+		"| **Total** | | **1,249** |\n" +
+		"\n" +
+		"**Repositories: 2**\n" +
+		"\n" +
+		"**Grand total downloads: 1,249**\n"
+)
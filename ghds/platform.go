@@ -0,0 +1,222 @@
+package ghds
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	PlatformOS      = "os"
+	PlatformArch    = "arch"
+	PlatformPackage = "package"
+
+	platformUnknown = "unknown"
+)
+
+// Platform describes the operating system, architecture and package type an
+// asset was built for, as derived from its name.
+type Platform struct {
+	OS      string `json:"os"`
+	Arch    string `json:"arch"`
+	Package string `json:"package"`
+}
+
+// ClassifierRule assigns Value to a field of an asset's Platform when Pattern
+// matches the asset's lower-cased name. Field is one of "os", "arch" or
+// "package".
+type ClassifierRule struct {
+	Field   string `json:"field"`
+	Value   string `json:"value"`
+	Pattern string `json:"pattern"`
+
+	re *regexp.Regexp
+}
+
+// word matches any of the alternatives as a separate token of an asset name,
+// e.g. "linux" in "tool_1.0_linux_amd64.tar.gz" but not in "linuxbrew".
+func word(alternatives string) string {
+	return `(^|[^a-z0-9])(` + alternatives + `)([^a-z0-9]|$)`
+}
+
+// DefaultClassifierRules recognise the naming conventions of common release
+// tooling such as GoReleaser, Rust's target triples and Linux packages.
+var DefaultClassifierRules = []ClassifierRule{
+	{Field: PlatformOS, Value: "darwin", Pattern: word(`darwin|macos|osx|mac|apple`) + `|\.dmg$|\.pkg$`},
+	{Field: PlatformOS, Value: "windows", Pattern: word(`windows|win|win32|win64|mingw`) + `|\.exe$|\.msi$`},
+	{Field: PlatformOS, Value: "freebsd", Pattern: word(`freebsd`)},
+	{Field: PlatformOS, Value: "openbsd", Pattern: word(`openbsd`)},
+	{Field: PlatformOS, Value: "netbsd", Pattern: word(`netbsd`)},
+	{Field: PlatformOS, Value: "android", Pattern: word(`android`) + `|\.apk$`},
+	{Field: PlatformOS, Value: "linux", Pattern: word(`linux|musl|gnu`) + `|\.deb$|\.rpm$|\.appimage$|\.snap$`},
+
+	{Field: PlatformArch, Value: "amd64", Pattern: word(`amd64|x86_64|x86-64|x64|64bit|64-bit`)},
+	{Field: PlatformArch, Value: "arm64", Pattern: word(`arm64|aarch64|armv8`)},
+	{Field: PlatformArch, Value: "386", Pattern: word(`386|i386|i686|x86|32bit|32-bit`)},
+	{Field: PlatformArch, Value: "arm", Pattern: word(`arm|armv5|armv6|armv7|armhf|armel`)},
+	{Field: PlatformArch, Value: "ppc64le", Pattern: word(`ppc64le|ppc64el`)},
+	{Field: PlatformArch, Value: "s390x", Pattern: word(`s390x`)},
+	{Field: PlatformArch, Value: "riscv64", Pattern: word(`riscv64`)},
+	{Field: PlatformArch, Value: "universal", Pattern: word(`universal|all`)},
+
+	{Field: PlatformPackage, Value: "tar.gz", Pattern: `\.(tar\.gz|tgz)$`},
+	{Field: PlatformPackage, Value: "tar.xz", Pattern: `\.(tar\.xz|txz)$`},
+	{Field: PlatformPackage, Value: "tar.bz2", Pattern: `\.(tar\.bz2|tbz2?)$`},
+	{Field: PlatformPackage, Value: "zip", Pattern: `\.zip$`},
+	{Field: PlatformPackage, Value: "deb", Pattern: `\.deb$`},
+	{Field: PlatformPackage, Value: "rpm", Pattern: `\.rpm$`},
+	{Field: PlatformPackage, Value: "apk", Pattern: `\.apk$`},
+	{Field: PlatformPackage, Value: "msi", Pattern: `\.msi$`},
+	{Field: PlatformPackage, Value: "exe", Pattern: `\.exe$`},
+	{Field: PlatformPackage, Value: "dmg", Pattern: `\.dmg$`},
+	{Field: PlatformPackage, Value: "pkg", Pattern: `\.pkg$`},
+	{Field: PlatformPackage, Value: "appimage", Pattern: `\.appimage$`},
+	{Field: PlatformPackage, Value: "snap", Pattern: `\.snap$`},
+	{Field: PlatformPackage, Value: "jar", Pattern: `\.jar$`},
+}
+
+// AssetClassifier derives a Platform from asset names using an ordered list
+// of rules. For each field, the first matching rule wins.
+type AssetClassifier struct {
+	rules []ClassifierRule
+}
+
+// NewAssetClassifier returns a classifier that tries the given rules before
+// DefaultClassifierRules, so they can override or extend the defaults.
+func NewAssetClassifier(rules []ClassifierRule) (*AssetClassifier, error) {
+	all := append(append([]ClassifierRule{}, rules...), DefaultClassifierRules...)
+	for i := range all {
+		switch all[i].Field {
+		case PlatformOS, PlatformArch, PlatformPackage:
+		default:
+			return nil, fmt.Errorf("classifier rule %q: unknown field %q", all[i].Pattern, all[i].Field)
+		}
+
+		re, err := regexp.Compile(all[i].Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("classifier rule %q: %s", all[i].Pattern, err)
+		}
+		all[i].re = re
+	}
+
+	return &AssetClassifier{rules: all}, nil
+}
+
+// LoadClassifierRules reads a JSON array of classifier rules from a file.
+func LoadClassifierRules(path string) ([]ClassifierRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	rules := []ClassifierRule{}
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err)
+	}
+	return rules, nil
+}
+
+func (c *AssetClassifier) Classify(name string) Platform {
+	name = strings.ToLower(name)
+	matched := map[string]string{}
+	for _, rule := range c.rules {
+		if _, ok := matched[rule.Field]; ok {
+			continue
+		}
+		if rule.re.MatchString(name) {
+			matched[rule.Field] = rule.Value
+		}
+	}
+
+	platform := Platform{
+		OS:      matched[PlatformOS],
+		Arch:    matched[PlatformArch],
+		Package: matched[PlatformPackage],
+	}
+	if platform.OS == "" {
+		platform.OS = platformUnknown
+	}
+	if platform.Arch == "" {
+		platform.Arch = platformUnknown
+	}
+	if platform.Package == "" {
+		// Assets built for a platform without a recognised extension are
+		// usually bare executables.
+		if platform.OS != platformUnknown && !strings.Contains(name[strings.LastIndexAny(name, "_-")+1:], ".") {
+			platform.Package = "binary"
+		} else {
+			platform.Package = platformUnknown
+		}
+	}
+	return platform
+}
+
+// PlatformBreakdown aggregates downloads by operating system, architecture
+// and package type across every release of one or more repositories.
+type PlatformBreakdown struct {
+	Repositories   []string        `json:"repositories"`
+	OS             []PlatformCount `json:"os"`
+	Arch           []PlatformCount `json:"arch"`
+	Package        []PlatformCount `json:"package"`
+	TotalDownloads int             `json:"total_downloads"`
+}
+
+type PlatformCount struct {
+	Name      string `json:"name"`
+	Assets    int    `json:"assets"`
+	Downloads int    `json:"download_count"`
+}
+
+func (c *AssetClassifier) Breakdown(histories ...*ReleaseHistory) *PlatformBreakdown {
+	counts := map[string]map[string]*PlatformCount{
+		PlatformOS:      {},
+		PlatformArch:    {},
+		PlatformPackage: {},
+	}
+	add := func(field string, value string, downloads int) {
+		pc, ok := counts[field][value]
+		if !ok {
+			pc = &PlatformCount{Name: value}
+			counts[field][value] = pc
+		}
+		pc.Assets++
+		pc.Downloads += downloads
+	}
+
+	breakdown := &PlatformBreakdown{Repositories: []string{}}
+	for _, history := range histories {
+		breakdown.Repositories = append(breakdown.Repositories, history.Repository)
+		for _, rel := range history.Releases {
+			for _, asset := range rel.Assets {
+				platform := c.Classify(asset.Name)
+				add(PlatformOS, platform.OS, asset.Downloads)
+				add(PlatformArch, platform.Arch, asset.Downloads)
+				add(PlatformPackage, platform.Package, asset.Downloads)
+				breakdown.TotalDownloads += asset.Downloads
+			}
+		}
+	}
+
+	breakdown.OS = sortedPlatformCounts(counts[PlatformOS])
+	breakdown.Arch = sortedPlatformCounts(counts[PlatformArch])
+	breakdown.Package = sortedPlatformCounts(counts[PlatformPackage])
+	return breakdown
+}
+
+// sortedPlatformCounts orders counts by downloads, most first, then by name.
+func sortedPlatformCounts(counts map[string]*PlatformCount) []PlatformCount {
+	sorted := make([]PlatformCount, 0, len(counts))
+	for _, pc := range counts {
+		sorted = append(sorted, *pc)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Downloads != sorted[j].Downloads {
+			return sorted[i].Downloads > sorted[j].Downloads
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
@@ -0,0 +1,177 @@
+package ghds
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClassifyAsset(t *testing.T) {
+	classifier, err := NewAssetClassifier(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var classifyTests = []struct {
+		name     string
+		expected Platform
+	}{
+		{"tool_1.2.3_linux_amd64.tar.gz", Platform{"linux", "amd64", "tar.gz"}},
+		{"tool_1.2.3_darwin_arm64.zip", Platform{"darwin", "arm64", "zip"}},
+		{"tool_1.2.3_windows_386.zip", Platform{"windows", "386", "zip"}},
+		{"tool-1.2.3-x86_64-unknown-linux-musl.tar.xz", Platform{"linux", "amd64", "tar.xz"}},
+		{"tool-1.2.3-aarch64-apple-darwin.tar.gz", Platform{"darwin", "arm64", "tar.gz"}},
+		{"tool-1.2.3-x86_64-pc-windows-gnu.zip", Platform{"windows", "amd64", "zip"}},
+		{"tool_1.2.3_amd64.deb", Platform{"linux", "amd64", "deb"}},
+		{"tool-1.2.3-1.aarch64.rpm", Platform{"linux", "arm64", "rpm"}},
+		{"tool-1.2.3-x64.msi", Platform{"windows", "amd64", "msi"}},
+		{"Tool-1.2.3.dmg", Platform{"darwin", "unknown", "dmg"}},
+		{"tool_linux_armv7", Platform{"linux", "arm", "binary"}},
+		{"checksums.txt", Platform{"unknown", "unknown", "unknown"}},
+	}
+
+	for _, tt := range classifyTests {
+		actual := classifier.Classify(tt.name)
+		if actual != tt.expected {
+			t.Errorf("Classify(%s): expected %+v, actual %+v", tt.name, tt.expected, actual)
+		}
+	}
+}
+
+func TestClassifierRulesOverrideDefaults(t *testing.T) {
+	classifier, err := NewAssetClassifier([]ClassifierRule{
+		{Field: PlatformOS, Value: "macos", Pattern: `darwin`},
+		{Field: PlatformPackage, Value: "checksum", Pattern: `checksums\.txt$`},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if actual := classifier.Classify("tool_darwin_amd64.zip"); actual.OS != "macos" {
+		t.Errorf("expected custom OS rule to win, got %+v", actual)
+	}
+	if actual := classifier.Classify("checksums.txt"); actual.Package != "checksum" {
+		t.Errorf("expected custom package rule to match, got %+v", actual)
+	}
+
+	if _, err := NewAssetClassifier([]ClassifierRule{{Field: "libc", Value: "musl", Pattern: "musl"}}); err == nil {
+		t.Errorf("expected error for unknown field")
+	}
+	if _, err := NewAssetClassifier([]ClassifierRule{{Field: PlatformOS, Value: "linux", Pattern: "("}}); err == nil {
+		t.Errorf("expected error for invalid pattern")
+	}
+}
+
+func TestPlatformBreakdown(t *testing.T) {
+	classifier, err := NewAssetClassifier(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	history := &ReleaseHistory{
+		Repository: "foo/bar",
+		Releases: []Release{
+			{Assets: []ReleaseAsset{
+				{Name: "tool_1.1.0_linux_amd64.tar.gz", Downloads: 40},
+				{Name: "tool_1.1.0_darwin_arm64.tar.gz", Downloads: 10},
+			}},
+			{Assets: []ReleaseAsset{
+				{Name: "tool_1.0.0_linux_amd64.tar.gz", Downloads: 20},
+				{Name: "tool_1.0.0_linux_arm64.zip", Downloads: 10},
+			}},
+		},
+	}
+
+	expected := &PlatformBreakdown{
+		Repositories: []string{"foo/bar"},
+		OS: []PlatformCount{
+			{Name: "linux", Assets: 3, Downloads: 70},
+			{Name: "darwin", Assets: 1, Downloads: 10},
+		},
+		Arch: []PlatformCount{
+			{Name: "amd64", Assets: 2, Downloads: 60},
+			{Name: "arm64", Assets: 2, Downloads: 20},
+		},
+		Package: []PlatformCount{
+			{Name: "tar.gz", Assets: 3, Downloads: 70},
+			{Name: "zip", Assets: 1, Downloads: 10},
+		},
+		TotalDownloads: 80,
+	}
+
+	actual := classifier.Breakdown(history)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("got %+v, expected %+v", actual, expected)
+	}
+}
+
+func TestFormatBreakdown(t *testing.T) {
+	breakdown := &PlatformBreakdown{
+		Repositories: []string{"foo/bar", "foo/baz"},
+		OS: []PlatformCount{
+			{Name: "linux", Assets: 3, Downloads: 7000},
+			{Name: "darwin", Assets: 1, Downloads: 1000},
+			{Name: "unknown", Assets: 1, Downloads: 3},
+		},
+		Arch: []PlatformCount{
+			{Name: "amd64", Assets: 2, Downloads: 6000},
+			{Name: "arm64", Assets: 2, Downloads: 2000},
+			{Name: "unknown", Assets: 1, Downloads: 3},
+		},
+		Package: []PlatformCount{
+			{Name: "tar.gz", Assets: 3, Downloads: 7000},
+			{Name: "zip", Assets: 1, Downloads: 1000},
+			{Name: "unknown", Assets: 1, Downloads: 3},
+		},
+		TotalDownloads: 8003,
+	}
+
+	var formatBreakdownTests = []struct {
+		format   string
+		expected string
+	}{
+		{FormatText, expectedBreakdownText},
+		{FormatCSV, expectedBreakdownCSV},
+	}
+
+	for _, tt := range formatBreakdownTests {
+		actual, err := FormatBreakdown(breakdown, tt.format)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", tt.format, err)
+		}
+		if actual != tt.expected {
+			t.Errorf("%s: got:\n%s\nexpected:\n%s", tt.format, actual, tt.expected)
+		}
+	}
+}
+
+const expectedBreakdownText = `Repositories: foo/bar, foo/baz
+
+ OS:       Assets: Downloads: Share:
+ - linux   3       7,000      87.5%
+ - darwin  1       1,000      12.5%
+ - unknown 1       3          0.0%
+
+ Architecture: Assets: Downloads: Share:
+ - amd64       2       6,000      75.0%
+ - arm64       2       2,000      25.0%
+ - unknown     1       3          0.0%
+
+ Package:  Assets: Downloads: Share:
+ - tar.gz  3       7,000      87.5%
+ - zip     1       1,000      12.5%
+ - unknown 1       3          0.0%
+
+Total downloads: 8,003
+`
+
+const expectedBreakdownCSV = `dimension,name,assets,downloads
+os,linux,3,7000
+os,darwin,1,1000
+os,unknown,1,3
+arch,amd64,2,6000
+arch,arm64,2,2000
+arch,unknown,1,3
+package,tar.gz,3,7000
+package,zip,1,1000
+package,unknown,1,3
+`
@@ -0,0 +1,60 @@
+package ghds
+
+import (
+	"sync"
+)
+
+// forEach calls fn for every index in [0, n) using at most concurrency
+// goroutines. Callers keep results in order by writing to index i of a
+// preallocated slice. Once any call fails no further calls are started, and
+// the error with the lowest index is returned so failures are deterministic.
+func forEach(n int, concurrency int, fn func(i int) error) error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if concurrency > n {
+		concurrency = n
+	}
+
+	errs := make([]error, n)
+	indexes := make(chan int)
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed bool
+	)
+
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if err := fn(i); err != nil {
+					errs[i] = err
+					mu.Lock()
+					failed = true
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		mu.Lock()
+		stop := failed
+		mu.Unlock()
+		if stop {
+			break
+		}
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
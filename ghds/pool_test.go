@@ -0,0 +1,53 @@
+package ghds
+
+import (
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+func TestForEach(t *testing.T) {
+	t.Run("results keep their order", func(t *testing.T) {
+		results := make([]int, 20)
+		var running, peak int32
+		err := forEach(len(results), 4, func(i int) error {
+			n := atomic.AddInt32(&running, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			results[i] = i * i
+			atomic.AddInt32(&running, -1)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		expected := make([]int, 20)
+		for i := range expected {
+			expected[i] = i * i
+		}
+		if !reflect.DeepEqual(results, expected) {
+			t.Errorf("got %v, expected %v", results, expected)
+		}
+		if peak > 4 {
+			t.Errorf("ran %d calls concurrently, expected at most 4", peak)
+		}
+	})
+
+	t.Run("the lowest failing index is reported", func(t *testing.T) {
+		err := forEach(10, 10, func(i int) error {
+			if i >= 3 {
+				return fmt.Errorf("failed %d", i)
+			}
+			return nil
+		})
+		if err == nil || err.Error() != "failed 3" {
+			t.Errorf("got %v, expected failed 3", err)
+		}
+	})
+}
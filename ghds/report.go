@@ -0,0 +1,73 @@
+package ghds
+
+import (
+	"context"
+	"sort"
+)
+
+// Report combines the release histories of several repositories.
+type Report struct {
+	Repositories    []*ReleaseHistory `json:"repositories"`
+	RepositoryCount int               `json:"repository_count"`
+	TotalDownloads  int               `json:"total_downloads"`
+}
+
+func NewReport(histories []*ReleaseHistory) *Report {
+	report := &Report{
+		Repositories:    histories,
+		RepositoryCount: len(histories),
+	}
+	for _, history := range histories {
+		report.TotalDownloads += history.TotalDownloads()
+	}
+	return report
+}
+
+// TotalDownloads sums the downloads of every release in the history.
+func (history *ReleaseHistory) TotalDownloads() int {
+	total := 0
+	for _, rel := range history.Releases {
+		total += rel.TotalDownloads
+	}
+	return total
+}
+
+// FetchReport fetches the release history of every service, at most
+// concurrency at a time, and combines them into a single report in the order
+// the services were given.
+func FetchReport(services []DownloadStatsService, concurrency int) (*Report, error) {
+	return FetchReportContext(context.Background(), services, concurrency)
+}
+
+// FetchReportContext is like FetchReport, but fetches with ctx. Once ctx is
+// done no further fetches are started.
+func FetchReportContext(ctx context.Context, services []DownloadStatsService, concurrency int) (*Report, error) {
+	histories := make([]*ReleaseHistory, len(services))
+	err := forEach(len(services), concurrency, func(i int) error {
+		history, err := fetchReleaseHistory(ctx, services[i])
+		histories[i] = history
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return NewReport(histories), nil
+}
+
+// Rank drops repositories without any releases and orders the remainder by
+// total downloads, most downloaded first.
+func (report *Report) Rank() {
+	ranked := []*ReleaseHistory{}
+	for _, history := range report.Repositories {
+		if history.ReleaseCount > 0 {
+			ranked = append(ranked, history)
+		}
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].TotalDownloads() > ranked[j].TotalDownloads()
+	})
+
+	*report = *NewReport(ranked)
+}
@@ -0,0 +1,106 @@
+package ghds
+
+import (
+	"context"
+	"testing"
+)
+
+// staticService is a DownloadStatsService without context support that
+// records whether it was asked to fetch.
+type staticService struct {
+	history *ReleaseHistory
+	fetched bool
+}
+
+func (s *staticService) FetchReleaseHistory() (*ReleaseHistory, error) {
+	s.fetched = true
+	return s.history, nil
+}
+
+func TestFetchReportContext(t *testing.T) {
+	services := []DownloadStatsService{
+		&staticService{history: &ReleaseHistory{Repository: "foo/bar"}},
+		&staticService{history: &ReleaseHistory{Repository: "foo/baz"}},
+	}
+
+	report, err := FetchReportContext(context.Background(), services, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if report.RepositoryCount != 2 || report.Repositories[1].Repository != "foo/baz" {
+		t.Errorf("expected foo/bar and foo/baz, got %+v", report.Repositories)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	services = []DownloadStatsService{&staticService{history: &ReleaseHistory{Repository: "foo/bar"}}}
+	if _, err := FetchReportContext(ctx, services, 1); err != context.Canceled {
+		t.Errorf("expected %v, got %v", context.Canceled, err)
+	}
+	if services[0].(*staticService).fetched {
+		t.Errorf("expected no fetch once the context is cancelled")
+	}
+}
+
+func TestFormatReport(t *testing.T) {
+	report := NewReport([]*ReleaseHistory{
+		&ReleaseHistory{
+			Repository: "foo/bar",
+			Releases: []Release{
+				Release{Name: "v1.0.0", Assets: []ReleaseAsset{{Name: "example.zip", Downloads: 42}}, TotalDownloads: 42},
+			},
+			ReleaseCount: 1,
+		},
+		&ReleaseHistory{
+			Repository: "foo/baz",
+			Releases: []Release{
+				Release{Name: "v2.0.0", Assets: []ReleaseAsset{{Name: "example.zip", Downloads: 85}}, TotalDownloads: 85},
+			},
+			ReleaseCount: 1,
+		},
+	})
+
+	if report.TotalDownloads != 127 {
+		t.Errorf("got total %d, expected 127", report.TotalDownloads)
+	}
+
+	actual, err := FormatReport(report, FormatText)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if actual != expectedReport {
+		t.Errorf("got %v, expected %v", actual, expectedReport)
+	}
+}
+
+const (
+	expectedReport = `Repository: foo/bar
+
+Release: v1.0.0 Date: 0001-01-01 00:00:00 +0000 UTC
+ 
+ Asset:        Downloads:
+ - example.zip  42
+
+Total downloads: 42
+
+------------------------------------------
+
+Repository: foo/baz
+
+Release: v2.0.0 Date: 0001-01-01 00:00:00 +0000 UTC
+ 
+ Asset:        Downloads:
+ - example.zip  85
+
+Total downloads: 85
+
+------------------------------------------
+
+ Repository: Downloads:
+ - foo/bar    42
+ - foo/baz    85
+
+Repositories:          2
+Grand total downloads: 127
+`
+)
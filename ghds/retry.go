@@ -0,0 +1,155 @@
+package ghds
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/github"
+)
+
+// retryBaseDelay is the delay before the first retry of a failed request;
+// each further retry doubles it.
+var retryBaseDelay = time.Second
+
+// defaultAbuseRetryAfter is used when a secondary rate limit response does
+// not say how long to wait.
+const defaultAbuseRetryAfter = time.Minute
+
+// QuotaUsage records the GitHub API requests made during a run and the rate
+// limit reported with the most recent response. It is safe for concurrent use
+// and may be shared by several services through GitHubDownloadStatsOptions.
+type QuotaUsage struct {
+	mu       sync.Mutex
+	requests int
+	retries  int
+	rate     github.Rate
+}
+
+func (u *QuotaUsage) record(resp *github.Response) {
+	if u == nil {
+		return
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.requests++
+	if resp != nil && resp.Rate.Limit > 0 {
+		u.rate = resp.Rate
+	}
+}
+
+func (u *QuotaUsage) recordRetry() {
+	if u == nil {
+		return
+	}
+	u.mu.Lock()
+	u.retries++
+	u.mu.Unlock()
+}
+
+// Requests returns the number of API requests made, including retries.
+func (u *QuotaUsage) Requests() int {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.requests
+}
+
+// Retries returns the number of requests that were retried.
+func (u *QuotaUsage) Retries() int {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.retries
+}
+
+// Rate returns the rate limit reported by the most recent response.
+func (u *QuotaUsage) Rate() github.Rate {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.rate
+}
+
+func (u *QuotaUsage) String() string {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	s := fmt.Sprintf("%d API requests (%d retries)", u.requests, u.retries)
+	if u.rate.Limit > 0 {
+		s += fmt.Sprintf(", %d of %d remaining until %v", u.rate.Remaining, u.rate.Limit, u.rate.Reset.Time)
+	}
+	return s
+}
+
+// retry calls fn until it succeeds or fails with an error that should not be
+// retried. Primary rate limits are waited out until they reset and secondary
+// rate limits for as long as the response asks, as long as the wait does not
+// exceed options.MaxWait. Server errors are retried with jittered exponential
+// backoff. At most options.MaxRetries retries are made.
+func retry(ctx context.Context, options *GitHubDownloadStatsOptions, fn func() (*github.Response, error)) (*github.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := fn()
+		options.Usage.record(resp)
+		if err == nil || attempt >= options.MaxRetries {
+			return resp, err
+		}
+
+		wait, rateLimited, ok := retryDelay(err, attempt)
+		if !ok || (rateLimited && wait > options.MaxWait) {
+			return resp, err
+		}
+		options.Usage.recordRetry()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return resp, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// retryDelay returns how long to wait before retrying after err, whether the
+// wait is due to a rate limit, and whether err should be retried at all.
+func retryDelay(err error, attempt int) (time.Duration, bool, bool) {
+	switch e := err.(type) {
+	case *github.RateLimitError:
+		wait := time.Until(e.Rate.Reset.Time) + time.Second
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true, true
+	case *github.AbuseRateLimitError:
+		if e.RetryAfter != nil {
+			return *e.RetryAfter, true, true
+		}
+		return defaultAbuseRetryAfter, true, true
+	case *github.ErrorResponse:
+		if e.Response == nil {
+			break
+		}
+		// Newer secondary rate limit responses are not recognised by the
+		// client as abuse rate limits, but still carry Retry-After.
+		if retryAfter := e.Response.Header.Get("Retry-After"); retryAfter != "" &&
+			(e.Response.StatusCode == http.StatusForbidden || e.Response.StatusCode == http.StatusTooManyRequests) {
+			seconds, err := strconv.Atoi(retryAfter)
+			if err != nil {
+				return defaultAbuseRetryAfter, true, true
+			}
+			return time.Duration(seconds) * time.Second, true, true
+		}
+		if e.Response.StatusCode >= http.StatusInternalServerError {
+			return backoff(attempt), false, true
+		}
+	}
+	return 0, false, false
+}
+
+// backoff returns an exponentially increasing delay with up to 50% jitter.
+func backoff(attempt int) time.Duration {
+	delay := retryBaseDelay << uint(attempt)
+	return delay + time.Duration(rand.Int63n(int64(delay)/2+1))
+}
@@ -0,0 +1,103 @@
+package ghds
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/github"
+)
+
+func TestFetchReleaseHistoryRetries(t *testing.T) {
+	retryBaseDelay = time.Millisecond
+	defer func() { retryBaseDelay = time.Second }()
+
+	var retryTests = []struct {
+		name     string
+		fail     func(w http.ResponseWriter)
+		failures int
+		options  GitHubDownloadStatsOptions
+		retried  bool
+	}{
+		{"server errors are retried", func(w http.ResponseWriter) {
+			http.Error(w, `{"message": "Server Error"}`, http.StatusBadGateway)
+		}, 2, GitHubDownloadStatsOptions{MaxRetries: 3}, true},
+		{"server errors are not retried past the maximum", func(w http.ResponseWriter) {
+			http.Error(w, `{"message": "Server Error"}`, http.StatusBadGateway)
+		}, 2, GitHubDownloadStatsOptions{MaxRetries: 1}, false},
+		{"secondary rate limits honour Retry-After", func(w http.ResponseWriter) {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"message": "You have triggered an abuse detection mechanism.", "documentation_url": "https://developer.github.com/v3/#abuse-rate-limits"}`)
+		}, 1, GitHubDownloadStatsOptions{MaxRetries: 3}, true},
+		{"rate limits are not waited out past the maximum wait", func(w http.ResponseWriter) {
+			w.Header().Set("X-RateLimit-Limit", "60")
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"message": "API rate limit exceeded for 127.0.0.1."}`)
+		}, 1, GitHubDownloadStatsOptions{MaxRetries: 3, MaxWait: time.Minute}, false},
+		{"client errors are not retried", func(w http.ResponseWriter) {
+			http.Error(w, `{"message": "Not Found"}`, http.StatusNotFound)
+		}, 1, GitHubDownloadStatsOptions{MaxRetries: 3}, false},
+	}
+
+	for _, tt := range retryTests {
+		t.Run(tt.name, func(t *testing.T) {
+			setup()
+			defer teardown()
+
+			requests := 0
+			mux.HandleFunc("/repos/foo/bar/releases", func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if requests <= tt.failures {
+					tt.fail(w)
+					return
+				}
+				fmt.Fprint(w, `[{"tag_name": "v1.0.0", "assets": [{"name": "example.zip", "download_count": 42}]}]`)
+			})
+
+			opts := tt.options
+			opts.ApiEndpoint = options.ApiEndpoint
+			opts.Usage = &QuotaUsage{}
+			dss := newTestService(t, "foo", "bar", &opts)
+			history, err := dss.FetchReleaseHistory()
+
+			if tt.retried {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				if history.ReleaseCount != 1 {
+					t.Errorf("got %d releases, expected 1", history.ReleaseCount)
+				}
+				if opts.Usage.Requests() != tt.failures+1 || opts.Usage.Retries() != tt.failures {
+					t.Errorf("unexpected usage: %s", opts.Usage)
+				}
+			} else if err == nil {
+				t.Errorf("expected error")
+			}
+		})
+	}
+}
+
+func TestRateLimitErrorIsReturned(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/repos/foo/bar/releases", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "60")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"message": "API rate limit exceeded for 127.0.0.1."}`)
+	})
+
+	_, err := newTestService(t, "foo", "bar", options).FetchReleaseHistory()
+	var rateLimitErr *github.RateLimitError
+	if !errors.As(err, &rateLimitErr) {
+		t.Fatalf("got %v, expected *github.RateLimitError", err)
+	}
+}
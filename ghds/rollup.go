@@ -0,0 +1,94 @@
+package ghds
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// VersionRollup sums a repository's downloads by major and by major.minor
+// version line, as parsed from release tags.
+type VersionRollup struct {
+	Repository     string        `json:"repository"`
+	Majors         []VersionLine `json:"majors"`
+	Minors         []VersionLine `json:"minors"`
+	Unversioned    *VersionLine  `json:"unversioned,omitempty"`
+	TotalDownloads int           `json:"total_downloads"`
+}
+
+// VersionLine totals the releases of one version line, e.g. "1.x" or "1.4.x".
+type VersionLine struct {
+	Line           string    `json:"line"`
+	ReleaseCount   int       `json:"release_count"`
+	TotalDownloads int       `json:"total_downloads"`
+	FirstRelease   time.Time `json:"first_release"`
+	LastRelease    time.Time `json:"last_release"`
+
+	version Version
+}
+
+func (line *VersionLine) add(rel Release) {
+	line.ReleaseCount++
+	line.TotalDownloads += rel.TotalDownloads
+	if line.FirstRelease.IsZero() || rel.Date.Before(line.FirstRelease) {
+		line.FirstRelease = rel.Date
+	}
+	if rel.Date.After(line.LastRelease) {
+		line.LastRelease = rel.Date
+	}
+}
+
+// Rollup groups the releases of a history by version line. Releases whose
+// tag and name are not versions are totalled as unversioned. Lines are
+// ordered newest first.
+func Rollup(history *ReleaseHistory) *VersionRollup {
+	majors := map[int]*VersionLine{}
+	minors := map[[2]int]*VersionLine{}
+	rollup := &VersionRollup{Repository: history.Repository}
+
+	for _, rel := range history.Releases {
+		rollup.TotalDownloads += rel.TotalDownloads
+
+		v, err := releaseVersion(rel)
+		if err != nil {
+			if rollup.Unversioned == nil {
+				rollup.Unversioned = &VersionLine{Line: "unversioned"}
+			}
+			rollup.Unversioned.add(rel)
+			continue
+		}
+
+		major, ok := majors[v.Major]
+		if !ok {
+			major = &VersionLine{Line: fmt.Sprintf("%d.x", v.Major), version: Version{Major: v.Major}}
+			majors[v.Major] = major
+		}
+		major.add(rel)
+
+		minor, ok := minors[[2]int{v.Major, v.Minor}]
+		if !ok {
+			minor = &VersionLine{Line: fmt.Sprintf("%d.%d.x", v.Major, v.Minor), version: Version{Major: v.Major, Minor: v.Minor}}
+			minors[[2]int{v.Major, v.Minor}] = minor
+		}
+		minor.add(rel)
+	}
+
+	rollup.Majors = make([]VersionLine, 0, len(majors))
+	for _, line := range majors {
+		rollup.Majors = append(rollup.Majors, *line)
+	}
+	rollup.Minors = make([]VersionLine, 0, len(minors))
+	for _, line := range minors {
+		rollup.Minors = append(rollup.Minors, *line)
+	}
+	sortVersionLines(rollup.Majors)
+	sortVersionLines(rollup.Minors)
+
+	return rollup
+}
+
+func sortVersionLines(lines []VersionLine) {
+	sort.Slice(lines, func(i, j int) bool {
+		return lines[i].version.Compare(lines[j].version) > 0
+	})
+}
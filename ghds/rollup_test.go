@@ -0,0 +1,121 @@
+package ghds
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRollup(t *testing.T) {
+	date := func(month time.Month) time.Time {
+		return time.Date(2024, month, 1, 0, 0, 0, 0, time.UTC)
+	}
+	history := &ReleaseHistory{
+		Repository: "o/r",
+		Releases: []Release{
+			{Tag: "v2.0.0", Date: date(6), TotalDownloads: 500},
+			{Tag: "v1.10.0", Date: date(5), TotalDownloads: 50},
+			{Tag: "v1.4.1", Date: date(3), TotalDownloads: 200},
+			{Tag: "v1.4.0", Date: date(2), TotalDownloads: 100},
+			{Tag: "nightly", Name: "1.9.0", Date: date(4), TotalDownloads: 25},
+			{Tag: "nightly-2024", Name: "Nightly", Date: date(7), TotalDownloads: 5},
+		},
+		ReleaseCount: 6,
+	}
+
+	rollup := Rollup(history)
+	if rollup.Repository != "o/r" || rollup.TotalDownloads != 880 {
+		t.Errorf("expected o/r with 880 downloads, actual %s with %d", rollup.Repository, rollup.TotalDownloads)
+	}
+
+	var majorTests = []VersionLine{
+		{Line: "2.x", ReleaseCount: 1, TotalDownloads: 500, FirstRelease: date(6), LastRelease: date(6)},
+		{Line: "1.x", ReleaseCount: 4, TotalDownloads: 375, FirstRelease: date(2), LastRelease: date(5)},
+	}
+	if len(rollup.Majors) != len(majorTests) {
+		t.Fatalf("expected %d majors, actual %+v", len(majorTests), rollup.Majors)
+	}
+	for i, expected := range majorTests {
+		rollup.Majors[i].version = Version{}
+		if !reflect.DeepEqual(rollup.Majors[i], expected) {
+			t.Errorf("major %d: expected %+v, actual %+v", i, expected, rollup.Majors[i])
+		}
+	}
+
+	lines := []string{}
+	for _, line := range rollup.Minors {
+		lines = append(lines, line.Line)
+	}
+	if expected := "2.0.x 1.10.x 1.9.x 1.4.x"; strings.Join(lines, " ") != expected {
+		t.Errorf("minors: expected %s, actual %v", expected, lines)
+	}
+	if minor := rollup.Minors[3]; minor.ReleaseCount != 2 || minor.TotalDownloads != 300 {
+		t.Errorf("1.4.x: expected 2 releases with 300 downloads, actual %+v", minor)
+	}
+
+	if rollup.Unversioned == nil || rollup.Unversioned.ReleaseCount != 1 || rollup.Unversioned.TotalDownloads != 5 {
+		t.Errorf("unversioned: expected 1 release with 5 downloads, actual %+v", rollup.Unversioned)
+	}
+}
+
+func TestFormatRollup(t *testing.T) {
+	date := func(month time.Month) time.Time {
+		return time.Date(2024, month, 1, 0, 0, 0, 0, time.UTC)
+	}
+	history := &ReleaseHistory{
+		Repository: "o/r",
+		Releases: []Release{
+			{Tag: "v2.0.0", Date: date(6), TotalDownloads: 5000},
+			{Tag: "v1.4.1", Date: date(3), TotalDownloads: 200},
+			{Tag: "v1.4.0", Date: date(2), TotalDownloads: 100},
+			{Tag: "nightly", Name: "Nightly", Date: date(7), TotalDownloads: 5},
+		},
+		ReleaseCount: 4,
+	}
+	rollups := []*VersionRollup{Rollup(history)}
+
+	var formatRollupTests = []struct {
+		format   string
+		expected string
+	}{
+		{FormatText, expectedRollupText},
+		{FormatCSV, expectedRollupCSV},
+	}
+
+	for _, tt := range formatRollupTests {
+		actual, err := FormatRollup(rollups, tt.format)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", tt.format, err)
+		}
+		if actual != tt.expected {
+			t.Errorf("%s: got:\n%s\nexpected:\n%s", tt.format, actual, tt.expected)
+		}
+	}
+}
+
+const expectedRollupText = `Repository: o/r
+
+ Major: Releases: Downloads: Share: First release: Last release:
+ - 2.x  1         5,000      94.3%  2024-06-01     2024-06-01
+ - 1.x  2         300        5.7%   2024-02-01     2024-03-01
+
+ Minor:  Releases: Downloads: Share: First release: Last release:
+ - 2.0.x 1         5,000      94.3%  2024-06-01     2024-06-01
+ - 1.4.x 2         300        5.7%   2024-02-01     2024-03-01
+
+ Other:        Releases: Downloads: Share: First release: Last release:
+ - unversioned 1         5          0.1%   2024-07-01     2024-07-01
+
+Total downloads: 5,305
+
+------------------------------------------
+`
+
+const expectedRollupCSV = `repository,level,line,releases,downloads,first_release,last_release
+o/r,major,2.x,1,5000,2024-06-01T00:00:00Z,2024-06-01T00:00:00Z
+o/r,major,1.x,2,300,2024-02-01T00:00:00Z,2024-03-01T00:00:00Z
+o/r,minor,2.0.x,1,5000,2024-06-01T00:00:00Z,2024-06-01T00:00:00Z
+o/r,minor,1.4.x,2,300,2024-02-01T00:00:00Z,2024-03-01T00:00:00Z
+o/r,unversioned,unversioned,1,5,2024-07-01T00:00:00Z,2024-07-01T00:00:00Z
+`
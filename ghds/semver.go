@@ -0,0 +1,361 @@
+package ghds
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a semantic version parsed from a release tag.
+type Version struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease []string
+	Build      string
+}
+
+// ParseVersion parses a tag such as "v1.2.3", "1.2.3-rc.1+build.5" or
+// "tool/v1.2". A leading "v" and any path prefix are ignored, and missing
+// minor and patch numbers are taken to be zero.
+func ParseVersion(tag string) (Version, error) {
+	s := tag
+	if i := strings.LastIndex(s, "/"); i >= 0 {
+		s = s[i+1:]
+	}
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "v"), "V")
+
+	v := Version{}
+	if i := strings.Index(s, "+"); i >= 0 {
+		v.Build = s[i+1:]
+		s = s[:i]
+	}
+	if i := strings.Index(s, "-"); i >= 0 {
+		if s[i+1:] == "" {
+			return Version{}, fmt.Errorf("invalid version %q: empty pre-release", tag)
+		}
+		v.Prerelease = strings.Split(s[i+1:], ".")
+		s = s[:i]
+	}
+
+	parts := strings.Split(s, ".")
+	if len(parts) > 3 {
+		return Version{}, fmt.Errorf("invalid version %q", tag)
+	}
+	numbers := []*int{&v.Major, &v.Minor, &v.Patch}
+	for i, part := range parts {
+		n, err := parseVersionNumber(part)
+		if err != nil {
+			return Version{}, fmt.Errorf("invalid version %q", tag)
+		}
+		*numbers[i] = n
+	}
+	return v, nil
+}
+
+// releaseVersion parses the version of a release from its tag, or from its
+// name when the tag is not a version.
+func releaseVersion(rel Release) (Version, error) {
+	v, err := ParseVersion(rel.Tag)
+	if err != nil {
+		return ParseVersion(rel.Name)
+	}
+	return v, nil
+}
+
+func parseVersionNumber(s string) (int, error) {
+	if s == "" || strings.TrimLeft(s, "0123456789") != "" {
+		return 0, fmt.Errorf("invalid version number %q", s)
+	}
+	return strconv.Atoi(s)
+}
+
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if len(v.Prerelease) > 0 {
+		s += "-" + strings.Join(v.Prerelease, ".")
+	}
+	if v.Build != "" {
+		s += "+" + v.Build
+	}
+	return s
+}
+
+// Compare returns -1, 0 or 1 as v is lower than, equal to or higher than
+// other, following semver precedence. Build metadata is ignored.
+func (v Version) Compare(other Version) int {
+	for _, d := range []int{v.Major - other.Major, v.Minor - other.Minor, v.Patch - other.Patch} {
+		if d != 0 {
+			return sign(d)
+		}
+	}
+
+	// A pre-release has lower precedence than the release itself.
+	switch {
+	case len(v.Prerelease) == 0 && len(other.Prerelease) == 0:
+		return 0
+	case len(v.Prerelease) == 0:
+		return 1
+	case len(other.Prerelease) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(v.Prerelease) && i < len(other.Prerelease); i++ {
+		a, b := v.Prerelease[i], other.Prerelease[i]
+		an, aErr := parseVersionNumber(a)
+		bn, bErr := parseVersionNumber(b)
+		switch {
+		case aErr == nil && bErr == nil:
+			if an != bn {
+				return sign(an - bn)
+			}
+		case aErr == nil:
+			return -1
+		case bErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(a, b); c != 0 {
+				return c
+			}
+		}
+	}
+	return sign(len(v.Prerelease) - len(other.Prerelease))
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	}
+	return 0
+}
+
+// comparator is a single bound of a version range, e.g. ">=1.2.0".
+type comparator struct {
+	op      string
+	version Version
+}
+
+func (c comparator) allows(v Version) bool {
+	cmp := v.Compare(c.version)
+	switch c.op {
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	}
+	return cmp == 0
+}
+
+// Constraint is a version range such as ">=1.2.0 <2.0.0", "^1.4", "~1.4.2"
+// or "1.x". Space separated comparators must all match, and alternatives may
+// be separated by "||".
+type Constraint struct {
+	// IncludePrerelease lets pre-release versions match whenever they are
+	// within the range, rather than only when the range names a pre-release
+	// of the same version.
+	IncludePrerelease bool
+
+	raw  string
+	sets [][]comparator
+}
+
+// ParseConstraint parses a version range. Besides the comparison operators
+// =, >, >=, < and <=, it accepts caret ranges (^1.4 allows changes that do
+// not modify the left-most non-zero number), tilde ranges (~1.4.2 allows
+// patch changes) and wildcards (1.x, 1.4.*, *).
+func ParseConstraint(s string) (*Constraint, error) {
+	c := &Constraint{raw: s}
+	alternatives := strings.Split(s, "||")
+	for _, alternative := range alternatives {
+		fields := strings.Fields(alternative)
+		if len(fields) == 0 && len(alternatives) > 1 {
+			return nil, fmt.Errorf("invalid version range %q: empty alternative", s)
+		}
+
+		set := []comparator{}
+		for _, field := range fields {
+			comparators, err := parseComparator(field)
+			if err != nil {
+				return nil, fmt.Errorf("invalid version range %q: %s", s, err)
+			}
+			set = append(set, comparators...)
+		}
+		c.sets = append(c.sets, set)
+	}
+	return c, nil
+}
+
+// partialVersion is a version whose trailing numbers may be wildcards or
+// missing; parts holds how many numbers were given.
+type partialVersion struct {
+	Version
+	parts int
+}
+
+func parsePartialVersion(s string) (partialVersion, error) {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "v"), "V")
+	if s == "" {
+		return partialVersion{}, fmt.Errorf("missing version")
+	}
+
+	core := s
+	if i := strings.IndexAny(s, "-+"); i >= 0 {
+		core = s[:i]
+	}
+	numbers := strings.Split(core, ".")
+	if len(numbers) > 3 {
+		return partialVersion{}, fmt.Errorf("invalid version %q", s)
+	}
+
+	parts := 0
+	for i, n := range numbers {
+		if n == "x" || n == "X" || n == "*" {
+			continue
+		}
+		if parts != i {
+			// Numbers may not follow a wildcard, as in 1.x.3.
+			return partialVersion{}, fmt.Errorf("invalid version %q", s)
+		}
+		parts++
+	}
+	// Only a full version may carry pre-release or build parts.
+	if parts < 3 && core != s {
+		return partialVersion{}, fmt.Errorf("invalid version %q", s)
+	}
+	if parts == 0 {
+		return partialVersion{}, nil
+	}
+
+	v, err := ParseVersion(strings.Join(numbers[:parts], ".") + s[len(core):])
+	if err != nil {
+		return partialVersion{}, err
+	}
+	return partialVersion{Version: v, parts: parts}, nil
+}
+
+// next returns the lowest version above every version matched by p, e.g.
+// 1.5.0 for 1.4 and 2.0.0 for 1.
+func (p partialVersion) next() Version {
+	switch p.parts {
+	case 1:
+		return Version{Major: p.Major + 1}
+	case 2:
+		return Version{Major: p.Major, Minor: p.Minor + 1}
+	}
+	return Version{Major: p.Major, Minor: p.Minor, Patch: p.Patch + 1}
+}
+
+// below returns an exclusive upper bound that also excludes the pre-releases
+// of v, so that ^1.4 never matches 2.0.0-rc.1.
+func below(v Version) comparator {
+	v.Prerelease = []string{"0"}
+	return comparator{op: "<", version: v}
+}
+
+func parseComparator(s string) ([]comparator, error) {
+	op := ""
+	for _, prefix := range []string{">=", "<=", ">", "<", "=", "^", "~"} {
+		if strings.HasPrefix(s, prefix) {
+			op, s = prefix, s[len(prefix):]
+			break
+		}
+	}
+
+	p, err := parsePartialVersion(s)
+	if err != nil {
+		return nil, err
+	}
+	if p.parts == 0 {
+		// "*" and friends match anything, except "<*" and ">*".
+		if op == "<" || op == ">" {
+			return []comparator{{op: "<", version: Version{}}}, nil
+		}
+		return []comparator{}, nil
+	}
+
+	lower := comparator{op: ">=", version: p.Version}
+	switch op {
+	case "^":
+		upper := Version{Major: p.Major + 1}
+		switch {
+		case p.Major == 0 && p.parts == 1:
+		case p.Major == 0 && (p.Minor > 0 || p.parts == 2):
+			upper = Version{Minor: p.Minor + 1}
+		case p.Major == 0:
+			upper = Version{Patch: p.Patch + 1}
+		}
+		return []comparator{lower, below(upper)}, nil
+	case "~":
+		if p.parts == 1 {
+			return []comparator{lower, below(p.next())}, nil
+		}
+		return []comparator{lower, below(Version{Major: p.Major, Minor: p.Minor + 1})}, nil
+	case ">":
+		if p.parts < 3 {
+			return []comparator{{op: ">=", version: p.next()}}, nil
+		}
+	case "<=":
+		if p.parts < 3 {
+			return []comparator{below(p.next())}, nil
+		}
+	case "", "=":
+		if p.parts < 3 {
+			return []comparator{lower, below(p.next())}, nil
+		}
+		op = "="
+	}
+	return []comparator{{op: op, version: p.Version}}, nil
+}
+
+// Allows reports whether v is within the range. As with npm, unless
+// IncludePrerelease is set a pre-release version only matches when a
+// comparator of the same alternative names a pre-release of the same major,
+// minor and patch version, so ">=1.2.0-rc.1" matches "1.2.0-rc.2" but
+// ">=1.0.0" does not match "1.3.0-beta".
+func (c *Constraint) Allows(v Version) bool {
+	for _, set := range c.sets {
+		if allowsAll(set, v, c.IncludePrerelease) {
+			return true
+		}
+	}
+	return false
+}
+
+func allowsAll(set []comparator, v Version, includePrerelease bool) bool {
+	for _, comp := range set {
+		if !comp.allows(v) {
+			return false
+		}
+	}
+	if len(v.Prerelease) == 0 || includePrerelease {
+		return true
+	}
+	for _, comp := range set {
+		cv := comp.version
+		if len(cv.Prerelease) > 0 && cv.Major == v.Major && cv.Minor == v.Minor && cv.Patch == v.Patch {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsTag parses tag as a version and reports whether it is within the
+// range. Tags that are not versions never match.
+func (c *Constraint) AllowsTag(tag string) bool {
+	v, err := ParseVersion(tag)
+	if err != nil {
+		return false
+	}
+	return c.Allows(v)
+}
+
+func (c *Constraint) String() string {
+	return c.raw
+}
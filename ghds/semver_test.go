@@ -0,0 +1,137 @@
+package ghds
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	var parseTests = []struct {
+		tag      string
+		expected Version
+		valid    bool
+	}{
+		{"v1.2.3", Version{Major: 1, Minor: 2, Patch: 3}, true},
+		{"1.2.3", Version{Major: 1, Minor: 2, Patch: 3}, true},
+		{"v2.0.0-rc.1+build.5", Version{Major: 2, Prerelease: []string{"rc", "1"}, Build: "build.5"}, true},
+		{"tool/v1.4", Version{Major: 1, Minor: 4}, true},
+		{"v3", Version{Major: 3}, true},
+		{"release-1.2.3", Version{}, false},
+		{"1.2.3.4", Version{}, false},
+		{"v1.2.3-", Version{}, false},
+		{"latest", Version{}, false},
+	}
+
+	for _, tt := range parseTests {
+		actual, err := ParseVersion(tt.tag)
+		if (err == nil) != tt.valid {
+			t.Errorf("ParseVersion(%s): expected valid %v, error %v", tt.tag, tt.valid, err)
+			continue
+		}
+		if tt.valid && !reflect.DeepEqual(actual, tt.expected) {
+			t.Errorf("ParseVersion(%s): expected %+v, actual %+v", tt.tag, tt.expected, actual)
+		}
+	}
+}
+
+func TestVersionCompare(t *testing.T) {
+	// Each version has lower precedence than the next.
+	ordered := []string{
+		"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta",
+		"1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.2.0", "v2.0.0",
+	}
+
+	for i := 0; i < len(ordered)-1; i++ {
+		a, _ := ParseVersion(ordered[i])
+		b, _ := ParseVersion(ordered[i+1])
+		if a.Compare(b) != -1 || b.Compare(a) != 1 {
+			t.Errorf("expected %s < %s", ordered[i], ordered[i+1])
+		}
+	}
+
+	a, _ := ParseVersion("v1.0.0+build.1")
+	b, _ := ParseVersion("1.0.0")
+	if a.Compare(b) != 0 {
+		t.Errorf("expected build metadata to be ignored")
+	}
+}
+
+func TestConstraintAllows(t *testing.T) {
+	var constraintTests = []struct {
+		constraint string
+		tag        string
+		expected   bool
+	}{
+		{">=1.2.0 <2.0.0", "v1.2.0", true},
+		{">=1.2.0 <2.0.0", "v1.9.9", true},
+		{">=1.2.0 <2.0.0", "v2.0.0", false},
+		{">=1.2.0 <2.0.0", "v1.1.9", false},
+		{">=1.2.0 <2.0.0", "v2.0.0-beta.1", false},
+		{">=1.2.0 <2.0.0", "v1.5.0-rc.1", false},
+		{"^1.4", "v1.4.0", true},
+		{"^1.4", "v1.9.3", true},
+		{"^1.4", "v1.3.9", false},
+		{"^1.4", "v2.0.0", false},
+		{"^0.4.2", "v0.4.9", true},
+		{"^0.4.2", "v0.5.0", false},
+		{"^0.0.3", "v0.0.4", false},
+		{"~1.4.2", "v1.4.7", true},
+		{"~1.4.2", "v1.4.1", false},
+		{"~1.4.2", "v1.5.0", false},
+		{"~1", "v1.9.0", true},
+		{"1.x", "v1.0.0", true},
+		{"1.x", "v1.99.0", true},
+		{"1.x", "v2.0.0", false},
+		{"1.4.*", "v1.4.3", true},
+		{"1.4.*", "v1.5.0", false},
+		{"1.4", "v1.4.3", true},
+		{"=1.4.2", "v1.4.2", true},
+		{"1.4.2", "v1.4.3", false},
+		{">1.4", "v1.4.9", false},
+		{">1.4", "v1.5.0", true},
+		{"<=1.4", "v1.4.9", true},
+		{"<=1.4", "v1.5.0", false},
+		{"*", "v3.1.0", true},
+		{"1.x || >=3.0.0", "v3.1.0", true},
+		{"1.x || >=3.0.0", "v2.1.0", false},
+		{">=2.0.0-rc.1", "v2.0.0-rc.2", true},
+		{">=2.0.0-rc.1", "v2.0.0", true},
+		{">=2.0.0-rc.1", "v2.1.0-rc.1", false},
+		{"1.x", "latest", false},
+	}
+
+	for _, tt := range constraintTests {
+		c, err := ParseConstraint(tt.constraint)
+		if err != nil {
+			t.Errorf("ParseConstraint(%s): unexpected error: %s", tt.constraint, err)
+			continue
+		}
+		actual := c.AllowsTag(tt.tag)
+		if actual != tt.expected {
+			t.Errorf("%q.AllowsTag(%s): expected %v, actual %v", tt.constraint, tt.tag, tt.expected, actual)
+		}
+	}
+}
+
+func TestConstraintIncludePrerelease(t *testing.T) {
+	c, err := ParseConstraint("^1.4")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	c.IncludePrerelease = true
+
+	if !c.AllowsTag("v1.5.0-rc.1") {
+		t.Errorf("expected pre-release within the range to match")
+	}
+	if c.AllowsTag("v2.0.0-rc.1") {
+		t.Errorf("expected pre-release outside the range not to match")
+	}
+}
+
+func TestParseConstraintErrors(t *testing.T) {
+	for _, s := range []string{">=foo", "^1.x.y", "1.2-rc.1", "1.x ||", ">=1.2.3.4"} {
+		if _, err := ParseConstraint(s); err == nil {
+			t.Errorf("ParseConstraint(%s): expected an error", s)
+		}
+	}
+}
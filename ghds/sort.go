@@ -0,0 +1,121 @@
+package ghds
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	SortDownloads = "downloads"
+	SortDate      = "date"
+	SortSemver    = "semver"
+	SortName      = "name"
+)
+
+// releaseComparers compare two releases by each sort key, returning a
+// negative number when a sorts before b in ascending order.
+var releaseComparers = map[string]func(a, b Release) int{
+	SortDownloads: func(a, b Release) int {
+		return a.TotalDownloads - b.TotalDownloads
+	},
+	SortDate: func(a, b Release) int {
+		return a.Date.Compare(b.Date)
+	},
+	SortSemver: compareReleaseVersions,
+	SortName: func(a, b Release) int {
+		return strings.Compare(releaseKey(a), releaseKey(b))
+	},
+}
+
+// SortKeys returns the keys releases can be sorted by.
+func SortKeys() []string {
+	keys := make([]string, 0, len(releaseComparers))
+	for key := range releaseComparers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ValidateSort returns an error if releases cannot be sorted by key.
+func ValidateSort(key string) error {
+	if _, ok := releaseComparers[key]; !ok && key != "" {
+		return fmt.Errorf("unknown sort key %q, expected one of %s", key, strings.Join(SortKeys(), ", "))
+	}
+	return nil
+}
+
+// SortReleases sorts releases by downloads, date, semantic version or name,
+// ascending unless desc is set. Releases that compare equal keep their order.
+// An empty key leaves the releases in the order GitHub lists them.
+func SortReleases(releases []Release, key string, desc bool) error {
+	if key == "" {
+		return nil
+	}
+	if err := ValidateSort(key); err != nil {
+		return err
+	}
+
+	compare := releaseComparers[key]
+	sort.SliceStable(releases, func(i, j int) bool {
+		if desc {
+			return compare(releases[j], releases[i]) < 0
+		}
+		return compare(releases[i], releases[j]) < 0
+	})
+	return nil
+}
+
+// compareReleaseVersions orders releases by the version in their tag, or
+// name, with releases that are not versions ordered first by date.
+func compareReleaseVersions(a, b Release) int {
+	av, aErr := releaseVersion(a)
+	bv, bErr := releaseVersion(b)
+	switch {
+	case aErr == nil && bErr == nil:
+		return av.Compare(bv)
+	case aErr == nil:
+		return 1
+	case bErr == nil:
+		return -1
+	}
+	return a.Date.Compare(b.Date)
+}
+
+// Limit keeps the first top releases of the history and the topAssets most
+// downloaded assets of each release. Zero means no limit. Release totals
+// still count every asset.
+func (history *ReleaseHistory) Limit(top int, topAssets int) {
+	if top > 0 && len(history.Releases) > top {
+		history.Releases = history.Releases[:top]
+		history.ReleaseCount = top
+	}
+
+	if topAssets <= 0 {
+		return
+	}
+	for i := range history.Releases {
+		assets := append([]ReleaseAsset{}, history.Releases[i].Assets...)
+		sort.SliceStable(assets, func(i, j int) bool {
+			return assets[i].Downloads > assets[j].Downloads
+		})
+		if len(assets) > topAssets {
+			assets = assets[:topAssets]
+		}
+		history.Releases[i].Assets = assets
+	}
+}
+
+// Arrange returns a copy of history sorted and limited as the Sort,
+// Descending, Top and TopAssets options ask, leaving history unchanged so
+// that it can still be stored or served in full.
+func (options *GitHubDownloadStatsOptions) Arrange(history *ReleaseHistory) (*ReleaseHistory, error) {
+	arranged := *history
+	arranged.Releases = append([]Release{}, history.Releases...)
+	if err := SortReleases(arranged.Releases, options.Sort, options.Descending); err != nil {
+		return nil, err
+	}
+	arranged.Limit(options.Top, options.TopAssets)
+	return &arranged, nil
+}
@@ -0,0 +1,109 @@
+package ghds
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func sortTestReleases() []Release {
+	date := func(month time.Month) time.Time {
+		return time.Date(2024, month, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return []Release{
+		{Name: "v1.10.0", Tag: "v1.10.0", Date: date(4), TotalDownloads: 30},
+		{Name: "nightly", Tag: "nightly", Date: date(5), TotalDownloads: 5},
+		{Name: "v1.9.0", Tag: "v1.9.0", Date: date(3), TotalDownloads: 300},
+		{Name: "v1.10.0-rc.1", Tag: "v1.10.0-rc.1", Date: date(3), TotalDownloads: 30},
+		{Name: "v0.1.0", Tag: "v0.1.0", Date: date(1), TotalDownloads: 100},
+	}
+}
+
+func TestSortReleases(t *testing.T) {
+	var sortTests = []struct {
+		key      string
+		desc     bool
+		expected []string
+	}{
+		{"", false, []string{"v1.10.0", "nightly", "v1.9.0", "v1.10.0-rc.1", "v0.1.0"}},
+		{SortDownloads, false, []string{"nightly", "v1.10.0", "v1.10.0-rc.1", "v0.1.0", "v1.9.0"}},
+		{SortDownloads, true, []string{"v1.9.0", "v0.1.0", "v1.10.0", "v1.10.0-rc.1", "nightly"}},
+		{SortDate, false, []string{"v0.1.0", "v1.9.0", "v1.10.0-rc.1", "v1.10.0", "nightly"}},
+		{SortSemver, false, []string{"nightly", "v0.1.0", "v1.9.0", "v1.10.0-rc.1", "v1.10.0"}},
+		{SortSemver, true, []string{"v1.10.0", "v1.10.0-rc.1", "v1.9.0", "v0.1.0", "nightly"}},
+		{SortName, false, []string{"nightly", "v0.1.0", "v1.10.0", "v1.10.0-rc.1", "v1.9.0"}},
+	}
+
+	for _, tt := range sortTests {
+		releases := sortTestReleases()
+		if err := SortReleases(releases, tt.key, tt.desc); err != nil {
+			t.Fatalf("SortReleases(%s, %v): unexpected error: %s", tt.key, tt.desc, err)
+		}
+
+		actual := []string{}
+		for _, rel := range releases {
+			actual = append(actual, rel.Tag)
+		}
+		if !reflect.DeepEqual(actual, tt.expected) {
+			t.Errorf("SortReleases(%s, %v): expected %v, actual %v", tt.key, tt.desc, tt.expected, actual)
+		}
+	}
+
+	if err := SortReleases(sortTestReleases(), "size", false); err == nil {
+		t.Errorf("expected error for unknown sort key")
+	}
+}
+
+func TestReleaseHistoryLimit(t *testing.T) {
+	history := &ReleaseHistory{
+		Releases: []Release{
+			{Tag: "v2.0.0", TotalDownloads: 60, Assets: []ReleaseAsset{
+				{Name: "a", Downloads: 10}, {Name: "b", Downloads: 30}, {Name: "c", Downloads: 20},
+			}},
+			{Tag: "v1.0.0", TotalDownloads: 5, Assets: []ReleaseAsset{{Name: "a", Downloads: 5}}},
+			{Tag: "v0.1.0"},
+		},
+		ReleaseCount: 3,
+	}
+
+	history.Limit(2, 2)
+	if history.ReleaseCount != 2 || len(history.Releases) != 2 {
+		t.Fatalf("expected 2 releases, actual %d: %+v", history.ReleaseCount, history.Releases)
+	}
+
+	expected := []ReleaseAsset{{Name: "b", Downloads: 30}, {Name: "c", Downloads: 20}}
+	if !reflect.DeepEqual(history.Releases[0].Assets, expected) {
+		t.Errorf("expected the most downloaded assets %+v, actual %+v", expected, history.Releases[0].Assets)
+	}
+	if history.Releases[0].TotalDownloads != 60 {
+		t.Errorf("expected release total to count every asset, actual %d", history.Releases[0].TotalDownloads)
+	}
+	if len(history.Releases[1].Assets) != 1 {
+		t.Errorf("expected 1 asset, actual %+v", history.Releases[1].Assets)
+	}
+
+	history.Limit(0, 0)
+	if history.ReleaseCount != 2 || len(history.Releases[0].Assets) != 2 {
+		t.Errorf("expected zero limits to keep everything")
+	}
+}
+
+func TestArrange(t *testing.T) {
+	history := &ReleaseHistory{Repository: "foo/bar", Releases: sortTestReleases(), ReleaseCount: 5}
+	options := &GitHubDownloadStatsOptions{Sort: SortDownloads, Descending: true, Top: 2}
+
+	arranged, err := options.Arrange(history)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if arranged.ReleaseCount != 2 || arranged.Releases[0].Tag != "v1.9.0" || arranged.Releases[1].Tag != "v0.1.0" {
+		t.Errorf("expected the 2 most downloaded releases, actual %+v", arranged.Releases)
+	}
+	if !reflect.DeepEqual(history.Releases, sortTestReleases()) || history.ReleaseCount != 5 {
+		t.Errorf("expected the original history to be unchanged, actual %+v", history)
+	}
+
+	if _, err := (&GitHubDownloadStatsOptions{Sort: "size"}).Arrange(history); err == nil {
+		t.Errorf("expected error for unknown sort key")
+	}
+}
@@ -0,0 +1,171 @@
+package ghds
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrNoSnapshot is returned when the store holds no snapshot matching a query.
+var ErrNoSnapshot = errors.New("no snapshot found")
+
+// Snapshot is a ReleaseHistory as it was fetched at a point in time.
+type Snapshot struct {
+	Timestamp time.Time       `json:"timestamp"`
+	History   *ReleaseHistory `json:"history"`
+}
+
+// SnapshotPoint is the download count of a single release asset at a point in time.
+type SnapshotPoint struct {
+	Timestamp  time.Time `json:"timestamp"`
+	Repository string    `json:"repository"`
+	Release    string    `json:"release"`
+	Tag        string    `json:"tag_name"`
+	Asset      string    `json:"asset"`
+	Downloads  int       `json:"download_count"`
+}
+
+// SnapshotStore persists snapshots in a local file, one JSON document per line.
+// Snapshots are only ever appended, so repeated runs accumulate a time series.
+type SnapshotStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewSnapshotStore(path string) *SnapshotStore {
+	return &SnapshotStore{path: path}
+}
+
+func (s *SnapshotStore) Save(history *ReleaseHistory, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	line, err := json.Marshal(Snapshot{Timestamp: at.UTC(), History: history})
+	if err != nil {
+		return err
+	}
+
+	if dir := filepath.Dir(s.path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// Snapshots returns all stored snapshots of a repository, oldest first.
+func (s *SnapshotStore) Snapshots(repository string) ([]Snapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return []Snapshot{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	snapshots := []Snapshot{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var snap Snapshot
+		if err := json.Unmarshal(scanner.Bytes(), &snap); err != nil {
+			return nil, fmt.Errorf("%s:%d: %s", s.path, lineNum, err)
+		}
+		if snap.History == nil || snap.History.Repository != repository {
+			continue
+		}
+		snapshots = append(snapshots, snap)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].Timestamp.Before(snapshots[j].Timestamp)
+	})
+
+	return snapshots, nil
+}
+
+// At returns the most recent snapshot of a repository taken at or before the given time.
+func (s *SnapshotStore) At(repository string, at time.Time) (*Snapshot, error) {
+	snapshots, err := s.Snapshots(repository)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		if !snapshots[i].Timestamp.After(at) {
+			return &snapshots[i], nil
+		}
+	}
+
+	return nil, ErrNoSnapshot
+}
+
+// Series returns the download history of a single asset of a release, oldest
+// first. The release is matched by tag name, or by name if it has no tag.
+func (s *SnapshotStore) Series(repository string, tag string, asset string) ([]SnapshotPoint, error) {
+	snapshots, err := s.Snapshots(repository)
+	if err != nil {
+		return nil, err
+	}
+
+	points := []SnapshotPoint{}
+	for _, snap := range snapshots {
+		for _, rel := range snap.History.Releases {
+			if releaseKey(rel) != tag {
+				continue
+			}
+			for _, a := range rel.Assets {
+				if a.Name != asset {
+					continue
+				}
+				points = append(points, SnapshotPoint{
+					Timestamp:  snap.Timestamp,
+					Repository: repository,
+					Release:    rel.Name,
+					Tag:        rel.Tag,
+					Asset:      a.Name,
+					Downloads:  a.Downloads,
+				})
+			}
+		}
+	}
+
+	return points, nil
+}
+
+func releaseKey(rel Release) string {
+	if rel.Tag != "" {
+		return rel.Tag
+	}
+	return rel.Name
+}
@@ -0,0 +1,85 @@
+package ghds
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func snapshotHistory(downloads int) *ReleaseHistory {
+	return &ReleaseHistory{
+		Repository: "foo/bar",
+		Releases: []Release{
+			Release{
+				Name: "v1.0.0",
+				Tag:  "v1.0.0",
+				Assets: []ReleaseAsset{
+					ReleaseAsset{
+						Name:      "example.zip",
+						Downloads: downloads,
+					},
+				},
+				TotalDownloads: downloads,
+			},
+		},
+		ReleaseCount: 1,
+	}
+}
+
+func TestSnapshotStore(t *testing.T) {
+	store := NewSnapshotStore(filepath.Join(t.TempDir(), "snapshots", "ghds.jsonl"))
+
+	timeOne := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	timeTwo := time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)
+
+	if _, err := store.At("foo/bar", timeTwo); err != ErrNoSnapshot {
+		t.Fatalf("expected ErrNoSnapshot from empty store, got %v", err)
+	}
+
+	if err := store.Save(snapshotHistory(42), timeOne); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := store.Save(snapshotHistory(85), timeTwo); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	other := snapshotHistory(7)
+	other.Repository = "foo/baz"
+	if err := store.Save(other, timeTwo); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	t.Run("snapshots are filtered by repository", func(t *testing.T) {
+		snapshots, err := store.Snapshots("foo/bar")
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if len(snapshots) != 2 {
+			t.Fatalf("got %d snapshots, expected 2", len(snapshots))
+		}
+	})
+
+	t.Run("at returns the latest snapshot before a time", func(t *testing.T) {
+		snap, err := store.At("foo/bar", timeTwo.Add(-time.Hour))
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(snap.History, snapshotHistory(42)) {
+			t.Errorf("got %v, expected %v", snap.History, snapshotHistory(42))
+		}
+	})
+
+	t.Run("series tracks a single asset", func(t *testing.T) {
+		expected := []SnapshotPoint{
+			{Timestamp: timeOne, Repository: "foo/bar", Release: "v1.0.0", Tag: "v1.0.0", Asset: "example.zip", Downloads: 42},
+			{Timestamp: timeTwo, Repository: "foo/bar", Release: "v1.0.0", Tag: "v1.0.0", Asset: "example.zip", Downloads: 85},
+		}
+		actual, err := store.Series("foo/bar", "v1.0.0", "example.zip")
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("got %v, expected %v", actual, expected)
+		}
+	})
+}
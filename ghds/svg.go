@@ -0,0 +1,242 @@
+package ghds
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LatestRelease selects the most recent release that is neither a draft nor a
+// pre-release when passed to DownloadsBadge.
+const LatestRelease = "latest"
+
+// Badge is a shields.io style badge, e.g. "downloads | 12.3k".
+type Badge struct {
+	Label   string
+	Message string
+	Color   string
+}
+
+// DownloadsBadge returns a badge counting the downloads of all releases of a
+// history if release is empty, of the latest release if it is LatestRelease,
+// or else of the release with that tag or name.
+func DownloadsBadge(history *ReleaseHistory, release string) (*Badge, error) {
+	badge := &Badge{Label: "downloads", Color: "#4c1"}
+	if release == "" {
+		badge.Message = HumanizeCount(history.TotalDownloads())
+		return badge, nil
+	}
+
+	rel, err := findRelease(history, release)
+	if err != nil {
+		return nil, err
+	}
+	badge.Label += "@" + release
+	badge.Message = HumanizeCount(rel.TotalDownloads)
+	return badge, nil
+}
+
+func findRelease(history *ReleaseHistory, release string) (*Release, error) {
+	if release == LatestRelease {
+		if latest := latestRelease(history.Releases); latest != nil {
+			return latest, nil
+		}
+	}
+	for i, rel := range history.Releases {
+		if rel.Tag == release || rel.Name == release {
+			return &history.Releases[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%s: release %q not found", history.Repository, release)
+}
+
+// latestRelease returns the most recent release that is neither a draft nor
+// a pre-release, as GitHub does, or else the most recent release.
+func latestRelease(releases []Release) *Release {
+	stable := func(rel *Release) bool {
+		return !rel.Draft && !rel.Prerelease
+	}
+
+	var latest *Release
+	for i := range releases {
+		rel := &releases[i]
+		if latest == nil || stable(rel) && !stable(latest) ||
+			stable(rel) == stable(latest) && rel.Date.After(latest.Date) {
+			latest = rel
+		}
+	}
+	return latest
+}
+
+// HumanizeCount abbreviates a count for display in a badge, e.g. 12.3k or 4M.
+func HumanizeCount(n int) string {
+	units := []string{"", "k", "M", "B", "T"}
+	f, i := float64(n), 0
+	for math.Abs(f) >= 999.95 && i < len(units)-1 {
+		f /= 1000
+		i++
+	}
+	if i == 0 {
+		return strconv.Itoa(n)
+	}
+	return strings.TrimSuffix(strconv.FormatFloat(f, 'f', 1, 64), ".0") + units[i]
+}
+
+// SVG renders the badge in the flat style of shields.io.
+func (b *Badge) SVG() string {
+	lw := textWidth(b.Label) + 10
+	mw := textWidth(b.Message) + 10
+	label, message, color := html.EscapeString(b.Label), html.EscapeString(b.Message), html.EscapeString(b.Color)
+
+	buf := new(bytes.Buffer)
+	fmt.Fprintf(buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s: %s">`, lw+mw, label, message)
+	fmt.Fprintf(buf, `<title>%s: %s</title>`, label, message)
+	fmt.Fprint(buf, `<linearGradient id="s" x2="0" y2="100%"><stop offset="0" stop-color="#bbb" stop-opacity=".1"/><stop offset="1" stop-opacity=".1"/></linearGradient>`)
+	fmt.Fprintf(buf, `<clipPath id="r"><rect width="%d" height="20" rx="3" fill="#fff"/></clipPath>`, lw+mw)
+	fmt.Fprintf(buf, `<g clip-path="url(#r)"><rect width="%d" height="20" fill="#555"/><rect x="%d" width="%d" height="20" fill="%s"/><rect width="%d" height="20" fill="url(#s)"/></g>`,
+		lw, lw, mw, color, lw+mw)
+	fmt.Fprint(buf, `<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">`)
+	for _, text := range []struct {
+		x    float64
+		text string
+	}{
+		{float64(lw) / 2, label},
+		{float64(lw) + float64(mw)/2, message},
+	} {
+		fmt.Fprintf(buf, `<text x="%.1f" y="15" fill="#010101" fill-opacity=".3">%s</text><text x="%.1f" y="14">%s</text>`,
+			text.x, text.text, text.x, text.text)
+	}
+	fmt.Fprint(buf, "</g></svg>\n")
+	return buf.String()
+}
+
+// textWidth estimates the width in pixels of s in 11px Verdana, which is
+// close enough to size a badge without font metrics.
+func textWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		switch {
+		case strings.ContainsRune("fijlrt.,:;|!'I1 ()-", r):
+			width += 4
+		case strings.ContainsRune("mwMW@%", r):
+			width += 10
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			width += 7
+		default:
+			width += 6
+		}
+	}
+	return width
+}
+
+const (
+	chartBarWidth  = 24
+	chartBarGap    = 12
+	chartHeight    = 200
+	chartTop       = 20
+	chartLeft      = 50
+	chartBottom    = 70
+	chartMinWidth  = 320
+	chartFontStyle = `font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="10"`
+)
+
+// ReleaseChartSVG renders a bar chart of the downloads of each release in a
+// history, oldest first.
+func ReleaseChartSVG(history *ReleaseHistory) string {
+	releases := append([]Release{}, history.Releases...)
+	sort.SliceStable(releases, func(i, j int) bool {
+		return releases[i].Date.Before(releases[j].Date)
+	})
+
+	max := 0
+	for _, rel := range releases {
+		if rel.TotalDownloads > max {
+			max = rel.TotalDownloads
+		}
+	}
+
+	width := chartLeft + len(releases)*(chartBarWidth+chartBarGap) + chartBarGap
+	if width < chartMinWidth {
+		width = chartMinWidth
+	}
+	height := chartTop + chartHeight + chartBottom
+	title := html.EscapeString(history.Repository) + " downloads per release"
+
+	buf := new(bytes.Buffer)
+	fmt.Fprintf(buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" role="img" aria-label="%s">`+"\n",
+		width, height, width, height, title)
+	fmt.Fprintf(buf, "<title>%s</title>\n", title)
+	fmt.Fprintf(buf, `<rect width="%d" height="%d" fill="#fff"/>`+"\n", width, height)
+	fmt.Fprintf(buf, "<g %s fill=\"#57606a\">\n", chartFontStyle)
+
+	if len(releases) == 0 {
+		fmt.Fprintf(buf, `<text x="%d" y="%d" text-anchor="middle">No releases found.</text>`+"\n", width/2, chartTop+chartHeight/2)
+		fmt.Fprint(buf, "</g>\n</svg>\n")
+		return buf.String()
+	}
+
+	// Grid lines at zero, half and the largest release.
+	for _, n := range []int{0, max / 2, max} {
+		y := chartTop + chartHeight - barHeight(n, max)
+		fmt.Fprintf(buf, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#d0d7de"/>`, chartLeft, y, width-chartBarGap, y)
+		fmt.Fprintf(buf, `<text x="%d" y="%d" text-anchor="end">%s</text>`+"\n", chartLeft-6, y+3, HumanizeCount(n))
+	}
+
+	for i, rel := range releases {
+		x := chartLeft + chartBarGap + i*(chartBarWidth+chartBarGap)
+		h := barHeight(rel.TotalDownloads, max)
+		y := chartTop + chartHeight - h
+		name := html.EscapeString(releaseKey(rel))
+		color := "#2da44e"
+		if rel.Draft || rel.Prerelease {
+			color = "#9be9a8"
+		}
+
+		fmt.Fprintf(buf, `<rect x="%d" y="%d" width="%d" height="%d" fill="%s"><title>%s%s: %s downloads</title></rect>`,
+			x, y, chartBarWidth, h, color, name, releaseMarker(rel), formatCount(rel.TotalDownloads))
+		fmt.Fprintf(buf, `<text x="%d" y="%d" text-anchor="middle">%s</text>`, x+chartBarWidth/2, y-4, HumanizeCount(rel.TotalDownloads))
+		lx, ly := x+chartBarWidth/2, chartTop+chartHeight+12
+		fmt.Fprintf(buf, `<text x="%d" y="%d" text-anchor="end" transform="rotate(-45 %d %d)">%s</text>`+"\n", lx, ly, lx, ly, name)
+	}
+
+	fmt.Fprint(buf, "</g>\n</svg>\n")
+	return buf.String()
+}
+
+func barHeight(n int, max int) int {
+	if max <= 0 {
+		return 0
+	}
+	return int(math.Round(float64(n) * chartHeight / float64(max)))
+}
+
+// badgeFormatter renders a downloads badge for a history.
+type badgeFormatter struct {
+	release string
+}
+
+// NewBadgeFormatter returns a formatter rendering a downloads badge for the
+// given release, as for DownloadsBadge.
+func NewBadgeFormatter(release string) Formatter {
+	return badgeFormatter{release: release}
+}
+
+func (f badgeFormatter) Format(history *ReleaseHistory) (string, error) {
+	badge, err := DownloadsBadge(history, f.release)
+	if err != nil {
+		return "", err
+	}
+	return badge.SVG(), nil
+}
+
+func (f badgeFormatter) FormatReport(report *Report) (string, error) {
+	if f.release != "" {
+		return "", fmt.Errorf("a badge for release %q needs a single repository", f.release)
+	}
+	badge := &Badge{Label: "downloads", Message: HumanizeCount(report.TotalDownloads), Color: "#4c1"}
+	return badge.SVG(), nil
+}
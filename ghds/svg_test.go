@@ -0,0 +1,101 @@
+package ghds
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHumanizeCount(t *testing.T) {
+	cases := map[int]string{
+		0:          "0",
+		999:        "999",
+		1000:       "1k",
+		12345:      "12.3k",
+		999949:     "999.9k",
+		999950:     "1M",
+		4200000:    "4.2M",
+		1500000000: "1.5B",
+		-1234:      "-1.2k",
+	}
+	for n, expected := range cases {
+		if actual := HumanizeCount(n); actual != expected {
+			t.Errorf("HumanizeCount(%d) = %q, expected %q", n, actual, expected)
+		}
+	}
+}
+
+var svgHistory = &ReleaseHistory{
+	Repository: "foo/bar",
+	Releases: []Release{
+		{Name: "v2.0.0-rc.1", Tag: "v2.0.0-rc.1", Date: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), Prerelease: true, TotalDownloads: 50},
+		{Name: "Second", Tag: "v1.1.0", Date: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), TotalDownloads: 12345},
+		{Name: "First <release>", Tag: "v1.0.0", Date: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), TotalDownloads: 6000},
+	},
+	ReleaseCount: 3,
+}
+
+func TestDownloadsBadge(t *testing.T) {
+	cases := []struct {
+		release string
+		label   string
+		message string
+	}{
+		{"", "downloads", "18.4k"},
+		{LatestRelease, "downloads@latest", "12.3k"},
+		{"v2.0.0-rc.1", "downloads@v2.0.0-rc.1", "50"},
+		{"First <release>", "downloads@First <release>", "6k"},
+	}
+	for _, c := range cases {
+		badge, err := DownloadsBadge(svgHistory, c.release)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %s", c.release, err)
+			continue
+		}
+		if badge.Label != c.label || badge.Message != c.message {
+			t.Errorf("%q: got %q | %q, expected %q | %q", c.release, badge.Label, badge.Message, c.label, c.message)
+		}
+	}
+
+	if _, err := DownloadsBadge(svgHistory, "v3.0.0"); err == nil {
+		t.Errorf("expected an error for an unknown release")
+	}
+	if _, err := DownloadsBadge(&ReleaseHistory{Repository: "foo/bar"}, LatestRelease); err == nil {
+		t.Errorf("expected an error for the latest release of an empty history")
+	}
+
+	svg := (&Badge{Label: "downloads@First <release>", Message: "6k", Color: "#4c1"}).SVG()
+	for _, expected := range []string{
+		`<title>downloads@First &lt;release&gt;: 6k</title>`,
+		`fill="#4c1"`,
+		`<text x="`,
+	} {
+		if !strings.Contains(svg, expected) {
+			t.Errorf("expected badge to contain %q, got %s", expected, svg)
+		}
+	}
+}
+
+func TestReleaseChartSVG(t *testing.T) {
+	svg := ReleaseChartSVG(svgHistory)
+
+	first := strings.Index(svg, "<title>v1.0.0: 6,000 downloads</title>")
+	second := strings.Index(svg, "<title>v1.1.0: 12,345 downloads</title>")
+	third := strings.Index(svg, "<title>v2.0.0-rc.1 (pre-release): 50 downloads</title>")
+	if first < 0 || second < 0 || third < 0 {
+		t.Fatalf("expected a bar for each release, got %s", svg)
+	}
+	if !(first < second && second < third) {
+		t.Errorf("expected releases to be charted oldest first")
+	}
+	if !strings.Contains(svg, `width="24" height="200" fill="#2da44e"><title>v1.1.0`) {
+		t.Errorf("expected the most downloaded release to fill the chart")
+	}
+	if !strings.Contains(svg, `width="24" height="97" fill="#2da44e"><title>v1.0.0`) {
+		t.Errorf("expected bars to be scaled to the most downloaded release")
+	}
+
+	if empty := ReleaseChartSVG(&ReleaseHistory{Repository: "foo/bar"}); !strings.Contains(empty, "No releases found.") {
+		t.Errorf("expected an empty chart to say so, got %s", empty)
+	}
+}
@@ -0,0 +1,141 @@
+package ghds
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var (
+	// ownerPattern follows GitHub's rules for user and organization names,
+	// which may contain alphanumerics and hyphens but not start with one.
+	// Underscores are allowed for GitHub Enterprise.
+	ownerPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,38}$`)
+	repoPattern  = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,100}$`)
+)
+
+// ValidationError reports an invalid argument or option passed to
+// NewGitHubDownloadStatsService, or a combination of options that cannot be
+// used together.
+type ValidationError struct {
+	// Field names the argument or GitHubDownloadStatsOptions field.
+	Field  string
+	Value  string
+	Reason string
+	// Err is the underlying error, if any, such as a version range syntax
+	// error.
+	Err error
+}
+
+func (e *ValidationError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("invalid %s: %s", e.Field, e.Err)
+	}
+	return fmt.Sprintf("invalid %s %q: %s", e.Field, e.Value, e.Reason)
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// ServiceOption customises a GitHubDownloadStatsService beyond what
+// GitHubDownloadStatsOptions covers.
+type ServiceOption func(*serviceConfig)
+
+type serviceConfig struct {
+	httpClient *http.Client
+}
+
+// WithHTTPClient makes the service send requests with client, e.g. to set
+// timeouts, a proxy or a caching transport. If a token is configured it is
+// added by wrapping the transport of a copy of client, which keeps its other
+// settings.
+func WithHTTPClient(client *http.Client) ServiceOption {
+	return func(c *serviceConfig) {
+		c.httpClient = client
+	}
+}
+
+// Validate checks that the options are well formed and do not conflict.
+// Errors are of type *ValidationError.
+func (options *GitHubDownloadStatsOptions) Validate() error {
+	if options.ApiEndpoint != "" {
+		if _, err := normalizeEndpoint(options.ApiEndpoint); err != nil {
+			return err
+		}
+	}
+
+	if options.Release != "" && options.Versions != "" {
+		return &ValidationError{Field: "Release", Value: options.Release, Reason: "cannot be combined with Versions"}
+	}
+	if options.Versions != "" {
+		if _, err := ParseConstraint(options.Versions); err != nil {
+			return &ValidationError{Field: "Versions", Value: options.Versions, Err: err}
+		}
+	}
+	if !options.Since.IsZero() && !options.Until.IsZero() && options.Since.After(options.Until) {
+		return &ValidationError{Field: "Since", Value: options.Since.String(), Reason: "must not be after Until"}
+	}
+	if _, err := NewAssetFilter(options.IncludeAssets, nil); err != nil {
+		return &ValidationError{Field: "IncludeAssets", Err: err}
+	}
+	if _, err := NewAssetFilter(nil, options.ExcludeAssets); err != nil {
+		return &ValidationError{Field: "ExcludeAssets", Err: err}
+	}
+	if err := ValidateSort(options.Sort); err != nil {
+		return &ValidationError{Field: "Sort", Value: options.Sort, Err: err}
+	}
+
+	for _, n := range []struct {
+		field string
+		value int
+	}{
+		{"Concurrency", options.Concurrency},
+		{"MaxRetries", options.MaxRetries},
+		{"Top", options.Top},
+		{"TopAssets", options.TopAssets},
+	} {
+		if n.value < 0 {
+			return &ValidationError{Field: n.field, Value: fmt.Sprint(n.value), Reason: "must not be negative"}
+		}
+	}
+	if options.MaxWait < 0 {
+		return &ValidationError{Field: "MaxWait", Value: options.MaxWait.String(), Reason: "must not be negative"}
+	}
+
+	return nil
+}
+
+func validateRepository(owner string, repo string) error {
+	if !ownerPattern.MatchString(owner) {
+		return &ValidationError{Field: "owner", Value: owner,
+			Reason: "must be at most 39 letters, digits, hyphens or underscores and not start with a hyphen"}
+	}
+	if !repoPattern.MatchString(repo) || repo == "." || repo == ".." {
+		return &ValidationError{Field: "repo", Value: repo,
+			Reason: "must be at most 100 letters, digits, hyphens, underscores or periods"}
+	}
+	return nil
+}
+
+// normalizeEndpoint parses an API endpoint and adds the trailing slash the
+// GitHub client requires, so that both "https://github.example.com/api/v3"
+// and "https://github.example.com/api/v3/" work.
+func normalizeEndpoint(endpoint string) (*url.URL, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, &ValidationError{Field: "ApiEndpoint", Value: endpoint, Err: err}
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, &ValidationError{Field: "ApiEndpoint", Value: endpoint, Reason: "scheme must be http or https"}
+	}
+	if u.Host == "" {
+		return nil, &ValidationError{Field: "ApiEndpoint", Value: endpoint, Reason: "missing host"}
+	}
+	if !strings.HasSuffix(u.Path, "/") {
+		u.Path += "/"
+	}
+	return u, nil
+}
@@ -1,9 +1,17 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/andrewsomething/github-download-stats/ghds"
 )
@@ -12,18 +20,88 @@ var (
 	version string
 	commit  string
 
-	owner       = flag.String("owner", "", "The GitHub repository's owner (required)")
-	repo        = flag.String("repo", "", "The GitHub repository (required)")
-	release     = flag.String("release", "", "The tag name of the release; excluding will list all releases")
-	jsonFlag    = flag.Bool("json", false, "Output in JSON")
-	endpoint    = flag.String("api-endpoint", "", "API endpoint for use with GitHub Enterprise")
-	token       = flag.String("token", os.Getenv("GITHUB_TOKEN"), "GitHub API token")
-	versionFlag = flag.Bool("version", false, "Print version")
-	preRelease  = flag.Bool("pre-release", false, "Include pre-releases")
+	owner         = flag.String("owner", "", "The GitHub repository's owner (required)")
+	repo          = flag.String("repo", "", "The GitHub repository (required)")
+	release       = flag.String("release", "", "The tag name of the release; excluding will list all releases")
+	versions      = flag.String("versions", "", "Only include releases whose tag is within a semantic version range, e.g. \">=1.2.0 <2.0.0\", ^1.4, ~1.4.2 or 1.x")
+	sinceFlag     = flag.String("since", "", "Only include releases published on or after this date, RFC 3339 time or age, e.g. 2024-01-01 or 90d")
+	untilFlag     = flag.String("until", "", "Only include releases published on or before this date, RFC 3339 time or age, e.g. 2024-06-30 or 30d")
+	noChecksums   = flag.Bool("exclude-checksums", false, "Exclude checksum, signature, certificate and SBOM assets")
+	sortFlag      = flag.String("sort", "", "Sort releases by "+strings.Join(ghds.SortKeys(), ", ")+"; excluding will keep the order GitHub lists them in")
+	desc          = flag.Bool("desc", false, "Sort in descending order")
+	top           = flag.Int("top", 0, "Only include the first N releases, after sorting")
+	topAssets     = flag.Int("top-assets", 0, "Only include the N most downloaded assets of each release")
+	format        = flag.String("format", ghds.FormatText, "Output format: "+strings.Join(ghds.Formatters(), ", "))
+	jsonFlag      = flag.Bool("json", false, "Output in JSON; same as -format json")
+	endpoint      = flag.String("api-endpoint", "", "API endpoint for use with GitHub Enterprise")
+	token         = flag.String("token", os.Getenv("GITHUB_TOKEN"), "GitHub API token")
+	versionFlag   = flag.Bool("version", false, "Print version")
+	preRelease    = flag.Bool("pre-release", false, "Include pre-releases")
+	drafts        = flag.Bool("drafts", false, "Include draft releases; requires a token with push access")
+	configPath    = flag.String("config", "", "Path to a JSON config file listing repositories to fetch; replaces -owner and -repo")
+	org           = flag.String("org", "", "Fetch every repository of a GitHub organization; replaces -owner and -repo")
+	user          = flag.String("user", "", "Fetch every repository of a GitHub user; replaces -owner and -repo")
+	archived      = flag.Bool("archived", false, "org/user: include archived repositories")
+	forks         = flag.Bool("forks", false, "org/user: include forked repositories")
+	publicOnly    = flag.Bool("public-only", false, "org/user: exclude private repositories")
+	concurrency   = flag.Int("concurrency", 4, "Maximum number of repositories, and pages of releases per repository, to fetch concurrently")
+	retries       = flag.Int("retries", 3, "Maximum number of times to retry a request after a server error or rate limit")
+	maxWait       = flag.Duration("max-wait", 5*time.Minute, "Maximum time to wait for a rate limit to reset before giving up")
+	quota         = flag.Bool("quota", false, "Print the GitHub API quota used by the run to stderr")
+	reportFlag    = flag.String("report", "releases", "Report to print: releases; platforms to aggregate downloads by OS, architecture and package type; lineage to follow each asset across releases; or rollup to sum downloads by major and minor version")
+	badgeRelease  = flag.String("badge-release", "", "svg-badge: count the downloads of this release tag or name, or of the \"latest\" release, instead of all releases")
+	platformRules = flag.String("platform-rules", "", "Path to a JSON file of rules for classifying asset names, tried before the built-in rules")
+	storePath     = flag.String("store", "", "Path to a snapshot file; each run appends the fetched release history")
+	fromFlag      = flag.String("from", "", "diff: compare against the latest snapshot taken at or before this time (required)")
+	toFlag        = flag.String("to", "", "diff: compare with the latest snapshot taken at or before this time; excluding will fetch live stats")
+	listen        = flag.String("listen", ":9184", "serve: address to listen on")
+	interval      = flag.Duration("interval", ghds.DefaultExporterInterval, "serve: how often to refresh download statistics")
+	cacheTTL      = flag.Duration("cache-ttl", 5*time.Minute, "serve: how long to cache release history for /badge and /repos requests")
 )
 
+const usageHeader = `Usage of %s:
+  %s [command] [flags]
+
+Commands:
+  stats    Print download statistics (default)
+  diff     Compare download statistics with a stored snapshot
+  serve    Serve download statistics as Prometheus metrics on /metrics, badges on /badge
+           and JSON on /repos
+
+Flags:
+`
+
+// patternList collects the values of a flag that may be repeated.
+type patternList []string
+
+var includeAssets, excludeAssets patternList
+
+func (p *patternList) String() string {
+	return strings.Join(*p, ", ")
+}
+
+func (p *patternList) Set(value string) error {
+	*p = append(*p, value)
+	return nil
+}
+
+func init() {
+	flag.Var(&includeAssets, "include-assets", "Only include assets whose name matches a `pattern`, either a glob or a regular expression prefixed with re:; may be repeated")
+	flag.Var(&excludeAssets, "exclude-assets", "Exclude assets whose name matches a `pattern`, either a glob or a regular expression prefixed with re:; may be repeated")
+}
+
 func main() {
-	flag.Parse()
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), usageHeader, os.Args[0], os.Args[0])
+		flag.PrintDefaults()
+	}
+
+	command := "stats"
+	args := os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
+	}
+	flag.CommandLine.Parse(args)
 
 	if *versionFlag {
 		if version == "" {
@@ -33,26 +111,457 @@ func main() {
 		os.Exit(0)
 	}
 
-	if *owner == "" || *repo == "" {
-		fmt.Println("Must set the repo and owner...")
-		flag.Usage()
+	if *jsonFlag {
+		*format = ghds.FormatJSON
+	}
+	if _, err := ghds.LookupFormatter(*format); err != nil {
+		fmt.Printf("Error: %s\n", err)
+		os.Exit(1)
+	}
+
+	if *versions != "" {
+		if _, err := ghds.ParseConstraint(*versions); err != nil {
+			fmt.Printf("Error: %s\n", err)
+			os.Exit(1)
+		}
+	}
+
+	var since, until time.Time
+	if *sinceFlag != "" {
+		var err error
+		since, err = parseSince(*sinceFlag, time.Now())
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			os.Exit(1)
+		}
+	}
+	if *untilFlag != "" {
+		var err error
+		until, err = parseUntil(*untilFlag, time.Now())
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if err := ghds.ValidateSort(*sortFlag); err != nil {
+		fmt.Printf("Error: %s\n", err)
+		os.Exit(1)
+	}
+	if err := validateReport(); err != nil {
+		fmt.Printf("Error: %s\n", err)
+		os.Exit(1)
+	}
+	if *badgeRelease != "" && *format != ghds.FormatSVGBadge {
+		fmt.Printf("Error: -badge-release requires -format %s\n", ghds.FormatSVGBadge)
+		os.Exit(1)
+	}
+
+	if *drafts && *token == "" && *configPath == "" {
+		fmt.Fprintln(os.Stderr, "Warning: GitHub only lists draft releases to tokens with push access")
+	}
+
+	if *noChecksums {
+		excludeAssets = append(excludeAssets, ghds.ChecksumAssetPatterns...)
+	}
+	if _, err := ghds.NewAssetFilter(includeAssets, excludeAssets); err != nil {
+		fmt.Printf("Error: %s\n", err)
+		os.Exit(1)
+	}
+
+	if !since.IsZero() && !until.IsZero() && since.After(until) {
+		fmt.Println("Error: -since must be before -until")
 		os.Exit(1)
 	}
 
 	options := &ghds.GitHubDownloadStatsOptions{
-		Release:     *release,
-		JsonOut:     *jsonFlag,
-		ApiEndpoint: *endpoint,
-		Token:       *token,
-		PreRelease:  *preRelease,
+		Release:       *release,
+		Versions:      *versions,
+		Since:         since,
+		Until:         until,
+		IncludeAssets: includeAssets,
+		ExcludeAssets: excludeAssets,
+		Format:        *format,
+		ApiEndpoint:   *endpoint,
+		Token:         *token,
+		PreRelease:    *preRelease,
+		Drafts:        *drafts,
+		Sort:          *sortFlag,
+		Descending:    *desc,
+		Top:           *top,
+		TopAssets:     *topAssets,
+		Concurrency:   *concurrency,
+		MaxRetries:    *retries,
+		MaxWait:       *maxWait,
+		Usage:         &ghds.QuotaUsage{},
+	}
+
+	if *configPath != "" && discovering() {
+		fmt.Println("Must set only one of -config, -org and -user...")
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	// Cancel fetches in progress on SIGINT or SIGTERM; a second signal
+	// terminates immediately.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	var services []*ghds.GitHubDownloadStatsService
+	if *configPath != "" {
+		config, err := ghds.LoadConfig(*configPath)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			os.Exit(1)
+		}
+		services, err = config.Services(options)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			os.Exit(1)
+		}
+	} else if discovering() {
+		discovery := &ghds.DiscoveryOptions{
+			Org:             *org,
+			User:            *user,
+			IncludeArchived: *archived,
+			IncludeForks:    *forks,
+			ExcludePrivate:  *publicOnly,
+		}
+		var err error
+		services, err = ghds.DiscoverRepositoriesContext(ctx, discovery, options)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			os.Exit(1)
+		}
+	} else {
+		if *owner == "" || *repo == "" {
+			fmt.Println("Must set the repo and owner...")
+			flag.Usage()
+			os.Exit(1)
+		}
+		dss, err := ghds.NewGitHubDownloadStatsService(*owner, *repo, options)
+		if err != nil {
+			fmt.Printf("Error: %s\n", err)
+			os.Exit(1)
+		}
+		services = append(services, dss)
 	}
 
-	dss := ghds.NewGitHubDownloadStatsService(*owner, *repo, options)
-	out, err := ghds.Build(dss)
+	var (
+		out string
+		err error
+	)
+	switch command {
+	case "stats":
+		out, err = runStats(ctx, services)
+	case "diff":
+		out, err = runDiff(ctx, services)
+	case "serve":
+		err = runServe(ctx, services)
+	default:
+		fmt.Printf("Unknown command: %s\n", command)
+		flag.Usage()
+		os.Exit(1)
+	}
+	if *quota {
+		fmt.Fprintf(os.Stderr, "Quota: %s\n", options.Usage)
+	}
 	if err != nil {
 		fmt.Printf("Error: %s\n", err)
 		os.Exit(1)
 	}
 
-	fmt.Println(out)
+	if out != "" {
+		fmt.Println(out)
+	}
+}
+
+func runStats(ctx context.Context, services []*ghds.GitHubDownloadStatsService) (string, error) {
+	report, err := ghds.FetchReportContext(ctx, downloadStatsServices(services), *concurrency)
+	if err != nil {
+		return "", err
+	}
+	if discovering() {
+		report.Rank()
+	}
+
+	if err := saveSnapshots(report.Repositories...); err != nil {
+		return "", err
+	}
+
+	switch *reportFlag {
+	case "releases":
+		if report, err = arrangeReport(report); err != nil {
+			return "", err
+		}
+		f, err := ghds.LookupFormatter(*format)
+		if err != nil {
+			return "", err
+		}
+		if *format == ghds.FormatHTML && *platformRules != "" {
+			classifier, err := assetClassifier()
+			if err != nil {
+				return "", err
+			}
+			f = ghds.NewHTMLFormatter(classifier)
+		}
+		if *format == ghds.FormatSVGBadge && *badgeRelease != "" {
+			f = ghds.NewBadgeFormatter(*badgeRelease)
+		}
+		return formatReleases(report, f)
+	case "platforms":
+		classifier, err := assetClassifier()
+		if err != nil {
+			return "", err
+		}
+		return ghds.FormatBreakdown(classifier.Breakdown(report.Repositories...), *format)
+	case "lineage":
+		lineages := make([]*ghds.AssetLineage, 0, len(report.Repositories))
+		for _, history := range report.Repositories {
+			lineages = append(lineages, ghds.Lineage(history))
+		}
+		return ghds.FormatLineage(lineages, *format)
+	case "rollup":
+		rollups := make([]*ghds.VersionRollup, 0, len(report.Repositories))
+		for _, history := range report.Repositories {
+			rollups = append(rollups, ghds.Rollup(history))
+		}
+		return ghds.FormatRollup(rollups, *format)
+	default:
+		return "", fmt.Errorf("unknown report %q", *reportFlag)
+	}
+}
+
+// validateReport checks -report, and that the flags sorting and limiting
+// releases are only used with the releases report, as the other reports
+// aggregate every release.
+func validateReport() error {
+	if !slices.Contains(reports, *reportFlag) {
+		return fmt.Errorf("unknown report %q, expected one of %s", *reportFlag, strings.Join(reports, ", "))
+	}
+	if *reportFlag != "releases" && (*sortFlag != "" || *desc || *top != 0 || *topAssets != 0) {
+		return fmt.Errorf("-sort, -desc, -top and -top-assets only apply to the releases report")
+	}
+	return nil
+}
+
+// formatReleases renders report with f, as a single history unless several
+// repositories were requested.
+func formatReleases(report *ghds.Report, f ghds.Formatter) (string, error) {
+	if *configPath == "" && !discovering() {
+		return f.Format(report.Repositories[0])
+	}
+	rf, ok := f.(ghds.ReportFormatter)
+	if !ok {
+		return "", fmt.Errorf("format %q does not support reports of several repositories", *format)
+	}
+	return rf.FormatReport(report)
+}
+
+// assetClassifier returns a classifier using the rules from -platform-rules,
+// if set, and the built-in rules.
+func assetClassifier() (*ghds.AssetClassifier, error) {
+	rules := []ghds.ClassifierRule{}
+	if *platformRules != "" {
+		var err error
+		if rules, err = ghds.LoadClassifierRules(*platformRules); err != nil {
+			return nil, err
+		}
+	}
+	return ghds.NewAssetClassifier(rules)
+}
+
+// reports are the values accepted by -report.
+var reports = []string{"releases", "platforms", "lineage", "rollup"}
+
+func discovering() bool {
+	return *org != "" || *user != ""
+}
+
+// arrangeReport sorts and limits each history for output. Snapshots and
+// rankings use the full histories.
+func arrangeReport(report *ghds.Report) (*ghds.Report, error) {
+	options := &ghds.GitHubDownloadStatsOptions{Sort: *sortFlag, Descending: *desc, Top: *top, TopAssets: *topAssets}
+	histories := make([]*ghds.ReleaseHistory, 0, len(report.Repositories))
+	for _, history := range report.Repositories {
+		arranged, err := options.Arrange(history)
+		if err != nil {
+			return nil, err
+		}
+		histories = append(histories, arranged)
+	}
+	return ghds.NewReport(histories), nil
+}
+
+func saveSnapshots(histories ...*ghds.ReleaseHistory) error {
+	if *storePath == "" {
+		return nil
+	}
+
+	store := ghds.NewSnapshotStore(*storePath)
+	now := time.Now().UTC().Truncate(time.Second)
+	for _, history := range histories {
+		if err := store.Save(history, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runDiff(ctx context.Context, services []*ghds.GitHubDownloadStatsService) (string, error) {
+	if *storePath == "" || *fromFlag == "" {
+		return "", fmt.Errorf("diff requires -store and -from")
+	}
+
+	fromTime, err := parseTime(*fromFlag)
+	if err != nil {
+		return "", err
+	}
+
+	var toTime time.Time
+	if *toFlag != "" {
+		toTime, err = parseTime(*toFlag)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	store := ghds.NewSnapshotStore(*storePath)
+	outputs := []string{}
+	for _, dss := range services {
+		repository := dss.Repository()
+
+		from, err := store.At(repository, fromTime)
+		if err != nil {
+			return "", fmt.Errorf("%s before %s: %s", repository, *fromFlag, err)
+		}
+
+		var to *ghds.Snapshot
+		if *toFlag != "" {
+			to, err = store.At(repository, toTime)
+			if err != nil {
+				return "", fmt.Errorf("%s before %s: %s", repository, *toFlag, err)
+			}
+		} else {
+			history, err := dss.FetchReleaseHistoryContext(ctx)
+			if err != nil {
+				return "", err
+			}
+			to = &ghds.Snapshot{Timestamp: time.Now().UTC().Truncate(time.Second), History: history}
+		}
+
+		out, err := ghds.FormatDiff(ghds.Diff(*from, *to), *format)
+		if err != nil {
+			return "", err
+		}
+		outputs = append(outputs, out)
+	}
+
+	return strings.Join(outputs, "\n"), nil
+}
+
+func runServe(ctx context.Context, services []*ghds.GitHubDownloadStatsService) error {
+	if *interval <= 0 {
+		return fmt.Errorf("-interval must be positive")
+	}
+	if *cacheTTL <= 0 {
+		return fmt.Errorf("-cache-ttl must be positive")
+	}
+
+	cached := map[string]ghds.DownloadStatsService{}
+	for _, s := range services {
+		cached[s.Repository()] = s
+	}
+	cache := ghds.NewHistoryCache(*cacheTTL, cached)
+
+	// The cache is filled by the exporter's refreshes, so that badge and API
+	// requests only fetch once the exporter's histories are older than the TTL.
+	exporter := ghds.NewExporter(*interval, downloadStatsServices(services)...)
+	exporter.Populate(cache)
+	go exporter.Run(ctx)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", exporter)
+	mux.Handle("/badge/", ghds.NewBadgeHandler(cache))
+	mux.Handle("/repos/", ghds.NewAPIHandler(cache))
+
+	server := &http.Server{Addr: *listen, Handler: mux}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	fmt.Printf("Serving metrics on %s/metrics, badges on %s/badge and JSON on %s/repos\n", *listen, *listen, *listen)
+	if err := server.ListenAndServe(); err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+func downloadStatsServices(services []*ghds.GitHubDownloadStatsService) []ghds.DownloadStatsService {
+	dss := make([]ghds.DownloadStatsService, 0, len(services))
+	for _, s := range services {
+		dss = append(dss, s)
+	}
+	return dss
+}
+
+// parseTime accepts either an RFC 3339 timestamp or a plain date.
+func parseTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q: expected YYYY-MM-DD or RFC 3339", value)
+	}
+
+	// A plain date refers to the end of that day, so snapshots taken on it are included.
+	return t.Add(24*time.Hour - time.Nanosecond), nil
+}
+
+// parseAge parses a relative age such as "90d", "2w" or "36h".
+func parseAge(value string) (time.Duration, bool) {
+	units := map[byte]time.Duration{'d': 24 * time.Hour, 'w': 7 * 24 * time.Hour}
+	if unit, ok := units[value[len(value)-1]]; ok {
+		n, err := strconv.Atoi(value[:len(value)-1])
+		return time.Duration(n) * unit, err == nil && n >= 0
+	}
+	d, err := time.ParseDuration(value)
+	return d, err == nil && d >= 0
+}
+
+// parseSince accepts an age, a plain date, which refers to the start of that
+// day, or an RFC 3339 timestamp.
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if age, ok := parseAge(value); ok {
+		return now.Add(-age), nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: expected YYYY-MM-DD, RFC 3339 or an age such as 90d", value)
+}
+
+// parseUntil is like parseSince, except that a plain date refers to the end
+// of that day.
+func parseUntil(value string, now time.Time) (time.Time, error) {
+	if age, ok := parseAge(value); ok {
+		return now.Add(-age), nil
+	}
+	t, err := parseTime(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q: expected YYYY-MM-DD, RFC 3339 or an age such as 30d", value)
+	}
+	return t, nil
 }
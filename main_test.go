@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andrewsomething/github-download-stats/ghds"
+)
+
+func TestRunStatsStoresFullHistory(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[
+  {"tag_name": "v1.1.0", "created_at": "2024-02-01T00:00:00Z", "assets": [{"name": "a.zip", "download_count": 5}, {"name": "b.zip", "download_count": 1}]},
+  {"tag_name": "v1.0.0", "created_at": "2024-01-01T00:00:00Z", "assets": [{"name": "a.zip", "download_count": 50}]}
+]`)
+	}))
+	defer server.Close()
+
+	dss, err := ghds.NewGitHubDownloadStatsService("foo", "bar", &ghds.GitHubDownloadStatsOptions{ApiEndpoint: server.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	*storePath = filepath.Join(t.TempDir(), "downloads.jsonl")
+	*sortFlag, *desc, *top, *topAssets = ghds.SortDownloads, true, 1, 1
+	*format = ghds.FormatJSON
+	defer func() {
+		*storePath, *sortFlag, *desc, *top, *topAssets, *format = "", "", false, 0, 0, ghds.FormatText
+	}()
+
+	out, err := runStats(context.Background(), []*ghds.GitHubDownloadStatsService{dss})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !strings.Contains(out, `"tag_name":"v1.0.0"`) || strings.Contains(out, `"tag_name":"v1.1.0"`) {
+		t.Errorf("expected only the most downloaded release in the output, got %s", out)
+	}
+
+	snapshots, err := ghds.NewSnapshotStore(*storePath).Snapshots("foo/bar")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(snapshots) != 1 {
+		t.Fatalf("expected 1 snapshot, got %d", len(snapshots))
+	}
+	history := snapshots[0].History
+	if len(history.Releases) != 2 || history.Releases[0].Tag != "v1.1.0" || len(history.Releases[0].Assets) != 2 {
+		t.Errorf("expected the snapshot to hold every release and asset in GitHub's order, got %+v", history.Releases)
+	}
+}
+
+func TestValidateReport(t *testing.T) {
+	defer func() {
+		*reportFlag, *sortFlag, *desc, *top, *topAssets = "releases", "", false, 0, 0
+	}()
+
+	var validateReportTests = []struct {
+		report    string
+		top       int
+		topAssets int
+		valid     bool
+	}{
+		{"releases", 1, 1, true},
+		{"rollup", 0, 0, true},
+		{"rollup", 1, 0, false},
+		{"platforms", 0, 1, false},
+		{"lineage", 1, 0, false},
+		{"unknown", 0, 0, false},
+	}
+
+	for _, tt := range validateReportTests {
+		*reportFlag, *top, *topAssets = tt.report, tt.top, tt.topAssets
+		err := validateReport()
+		if (err == nil) != tt.valid {
+			t.Errorf("%s -top %d -top-assets %d: got error %v, expected valid %v", tt.report, tt.top, tt.topAssets, err, tt.valid)
+		}
+	}
+}